@@ -3,7 +3,7 @@ buildGoModule {
   pname = "defang-cli";
   version = "git";
   src = ../../src;
-  vendorHash = "sha256-uXo+pCGMvzX1ukBBvCIu6+amC8cxEi8yIIAzhFZ8484=";
+  vendorHash = "sha256-XFkSpYOCxEENuRh+oflE1R9ZLqNTc4Bm3XRmvDmH+mA=";
 
   subPackages = [ "cmd/cli" ];
 
@@ -18,11 +18,14 @@ import (
 	"github.com/defang-io/defang/src/pkg"
 	"github.com/defang-io/defang/src/pkg/cli"
 	cliClient "github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/cli/client/byoc/aws"
+	awsClouds "github.com/defang-io/defang/src/pkg/clouds/aws"
 	"github.com/defang-io/defang/src/pkg/scope"
 	"github.com/defang-io/defang/src/pkg/term"
 	"github.com/defang-io/defang/src/pkg/types"
 	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const DEFANG_PORTAL_HOST = "portal.defang.dev"
@@ -133,6 +136,29 @@ func SetupCommands(version string) {
 	RootCmd.PersistentFlags().StringP("file", "f", "", `compose file path`)
 	RootCmd.MarkPersistentFlagFilename("file", "yml", "yaml")
 
+	// Flags for bring-your-own-cloud, only on the commands that use the provider
+	byocFlags := pflag.NewFlagSet("byoc", pflag.ExitOnError)
+	byocFlags.StringVar(&aws.Region, "region", aws.Region, "AWS region of the services; defaults to the CD region")
+	byocFlags.BoolVar(&aws.IgnoreRegionMismatch, "ignore-region-mismatch", aws.IgnoreRegionMismatch, "only warn when the services were deployed in another region than --region")
+	byocFlags.StringVar(&aws.CdRegion, "cd-region", aws.CdRegion, "AWS region of the CD stack; defaults to the region of the AWS profile")
+	byocFlags.StringVar(&awsClouds.RoleARN, "aws-role-arn", awsClouds.RoleARN, "AWS role to assume for all AWS operations, like deploying into another account")
+	byocFlags.StringVar(&awsClouds.ExternalID, "aws-external-id", awsClouds.ExternalID, "external ID to use when assuming the AWS role")
+	byocFlags.StringVar(&aws.VpcID, "vpc-id", aws.VpcID, "existing VPC for bring-your-own-cloud; tasks won't get public IPs, so it needs a NAT gateway")
+	byocFlags.StringVar(&aws.SubnetIDs, "subnet-ids", aws.SubnetIDs, "comma-separated private subnets of the existing VPC")
+	byocFlags.StringVar(&aws.Stack, "stack", aws.Stack, `stack (environment) to use with bring-your-own-cloud, like "staging"`)
+	byocFlags.StringVar(&aws.CdRoleARN, "cd-role-arn", aws.CdRoleARN, "existing IAM role for the CD task; see 'defang cd policy' for the required permissions")
+	byocFlags.StringVar(&aws.CdEgressCIDRs, "cd-egress-cidrs", aws.CdEgressCIDRs, "comma-separated CIDRs the CD task can connect to; all by default")
+	byocFlags.StringVar(&aws.CdSpot, "cd-spot", aws.CdSpot, "run the CD task on Spot capacity (true by default); use --cd-spot=false for long deployments")
+	byocFlags.Lookup("cd-spot").NoOptDefVal = "true"
+	byocFlags.IntVar(&aws.CdLogRetentionDays, "cd-log-retention", aws.CdLogRetentionDays, "number of days to keep the CD logs; 1 by default")
+	byocFlags.StringVar(&aws.CdLogDestinationARN, "cd-log-destination-arn", aws.CdLogDestinationARN, "Kinesis stream, Firehose, or Lambda function to send the CD logs to")
+	byocFlags.StringVar(&aws.CdLogDestinationRoleARN, "cd-log-destination-role-arn", aws.CdLogDestinationRoleARN, "IAM role that allows CloudWatch Logs to send to the log destination")
+	byocFlags.StringVar(&aws.CdPermissionsBoundary, "cd-permissions-boundary", aws.CdPermissionsBoundary, "ARN of the permissions boundary for the CD task role")
+	byocFlags.BoolVar(&aws.WaitForLock, "wait-for-lock", aws.WaitForLock, "wait for other CD operations to finish instead of failing")
+	for _, cmd := range []*cobra.Command{bootstrapCmd, composeCmd, configCmd, deploymentsCmd, rollbackCmd, getServicesCmd, tailCmd, execCmd, portForwardCmd, topCmd, waitCmd, deleteCmd, restartCmd} {
+		cmd.PersistentFlags().AddFlagSet(byocFlags)
+	}
+
 	// Bootstrap command
 	RootCmd.AddCommand(bootstrapCmd)
 	bootstrapCmd.AddCommand(bootstrapDestroyCmd)
@@ -142,6 +168,16 @@ func SetupCommands(version string) {
 	bootstrapCmd.AddCommand(bootstrapTearDownCmd)
 	bootstrapCmd.AddCommand(bootstrapListCmd)
 	bootstrapCmd.AddCommand(bootstrapCancelCmd)
+	bootstrapCmd.AddCommand(bootstrapTasksCmd)
+	bootstrapLogsCmd.Flags().String("export", "", "save the logs to files in this directory instead of showing them")
+	bootstrapLogsCmd.MarkFlagDirname("export")
+	bootstrapCmd.AddCommand(bootstrapLogsCmd)
+	bootstrapCmd.AddCommand(bootstrapUnlockCmd)
+	bootstrapCmd.AddCommand(bootstrapMigrateSecretsCmd)
+	bootstrapCmd.AddCommand(bootstrapPolicyCmd)
+	bootstrapSetUpCmd.Flags().Bool("preview", false, "only print the changes to the CD stack")
+	bootstrapCmd.AddCommand(bootstrapSetUpCmd)
+	bootstrapCmd.AddCommand(bootstrapDriftCmd)
 
 	// Eula command
 	tosCmd.Flags().Bool("agree-tos", false, "agree to the Defang terms of service")
@@ -168,6 +204,7 @@ func SetupCommands(version string) {
 
 	// Get Services Command
 	getServicesCmd.Flags().BoolP("long", "l", false, "show more details")
+	getServicesCmd.Flags().BoolP("watch", "w", false, "watch the services and redraw them when they change")
 	RootCmd.AddCommand(getServicesCmd)
 
 	// Get Status Command
@@ -187,6 +224,15 @@ func SetupCommands(version string) {
 	RootCmd.AddCommand(configCmd)
 	RootCmd.AddCommand(restartCmd)
 
+	// Deployments Command
+	deploymentsListCmd.Flags().Int("limit", 10, "maximum number of deployments to show; 0 for all (up to 100)")
+	deploymentsCmd.AddCommand(deploymentsListCmd)
+	RootCmd.AddCommand(deploymentsCmd)
+
+	// Rollback Command
+	rollbackCmd.Flags().Bool("tail", false, "tail the service logs after rolling back")
+	RootCmd.AddCommand(rollbackCmd)
+
 	// Compose Command
 	// composeCmd.Flags().Bool("compatibility", false, "Run compose in backward compatibility mode"); TODO: Implement compose option
 	// composeCmd.Flags().String("env-file", "", "Specify an alternate environment file."); TODO: Implement compose option
@@ -198,8 +244,13 @@ func SetupCommands(version string) {
 	composeUpCmd.Flags().MarkHidden("tail")
 	composeUpCmd.Flags().Bool("force", false, "force a build of the image even if nothing has changed")
 	composeUpCmd.Flags().BoolP("detach", "d", false, "run in detached mode")
+	composeUpCmd.Flags().Bool("wait", false, "wait for the services to be deployed; exits non-zero on failure")
+	composeUpCmd.Flags().Duration("wait-timeout", 0, "maximum duration to wait for the services to be deployed")
+	composeUpCmd.Flags().Bool("confirm", false, "show the deployment plan and ask for confirmation before deploying")
 	composeCmd.AddCommand(composeUpCmd)
 	composeCmd.AddCommand(composeConfigCmd)
+	composeCmd.AddCommand(composeDiffCmd)
+	composeCmd.AddCommand(composeEstimateCmd)
 	composeDownCmd.Flags().Bool("tail", false, "tail the service logs after deleting") // obsolete, but keep for backwards compatibility
 	composeDownCmd.Flags().BoolP("detach", "d", false, "run in detached mode")
 	composeDownCmd.Flags().MarkHidden("tail")
@@ -211,12 +262,29 @@ func SetupCommands(version string) {
 	composeCmd.AddCommand(composeStopCmd)
 
 	// Tail Command
-	tailCmd.Flags().StringP("name", "n", "", "name of the service")
+	tailCmd.Flags().StringSliceP("name", "n", nil, "name of the service(s); repeat or use a comma-separated list")
 	tailCmd.Flags().String("etag", "", "deployment ID (ETag) of the service")
 	tailCmd.Flags().BoolP("raw", "r", false, "show raw (unparsed) logs")
 	tailCmd.Flags().String("since", "5s", "show logs since duration/time")
 	RootCmd.AddCommand(tailCmd)
 
+	// Exec Command
+	RootCmd.AddCommand(execCmd)
+
+	// Port-forward Command
+	RootCmd.AddCommand(portForwardCmd)
+
+	// Top Command
+	topCmd.Flags().String("since", "5m", "show logs since duration/time")
+	RootCmd.AddCommand(topCmd)
+
+	// Wait Command
+	waitCmd.Flags().String("etag", "", "deployment ID (ETag) to wait for (required)")
+	waitCmd.Flags().Duration("timeout", 0, "maximum duration to wait for the services to be deployed")
+	waitCmd.Flags().Bool("tail", false, "tail the service logs while waiting")
+	waitCmd.MarkFlagRequired("etag")
+	RootCmd.AddCommand(waitCmd)
+
 	// Delete Command
 	deleteCmd.Flags().BoolP("name", "n", false, "name of the service(s) (backwards compat)")
 	deleteCmd.Flags().MarkHidden("name")
@@ -510,6 +578,11 @@ var getServicesCmd = &cobra.Command{
 	Short:       "Get list of services on the cluster",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		long, _ := cmd.Flags().GetBool("long")
+		watch, _ := cmd.Flags().GetBool("watch")
+
+		if watch {
+			return cli.WatchServices(cmd.Context(), client)
+		}
 
 		err := cli.GetServices(cmd.Context(), client, long)
 		if err != nil {
@@ -550,7 +623,7 @@ var tailCmd = &cobra.Command{
 	Args:        cobra.NoArgs,
 	Short:       "Tail logs from one or more services",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var name, _ = cmd.Flags().GetString("name")
+		var names, _ = cmd.Flags().GetStringSlice("name")
 		var etag, _ = cmd.Flags().GetString("etag")
 		var raw, _ = cmd.Flags().GetBool("raw")
 		var since, _ = cmd.Flags().GetString("since")
@@ -562,7 +635,7 @@ var tailCmd = &cobra.Command{
 
 		ts = ts.UTC()
 		term.Info(" * Showing logs since", ts.Format(time.RFC3339Nano), "; press Ctrl+C to stop:")
-		return cli.Tail(cmd.Context(), client, name, etag, ts, raw)
+		return cli.Tail(cmd.Context(), client, cli.TailOptions{Services: names, Etag: etag, Since: ts, Raw: raw})
 	},
 }
 
@@ -645,6 +718,52 @@ var configListCmd = &cobra.Command{
 	},
 }
 
+var deploymentsCmd = &cobra.Command{
+	Use:     "deployments",
+	Aliases: []string{"deployment", "history"},
+	Args:    cobra.NoArgs,
+	Short:   "Show the deployment history of the project",
+}
+
+var deploymentsListCmd = &cobra.Command{
+	Use:         "ls",
+	Annotations: authNeededAnnotation,
+	Args:        cobra.NoArgs,
+	Aliases:     []string{"list"},
+	Short:       "List past deployments, newest first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var limit, _ = cmd.Flags().GetInt("limit")
+
+		return cli.ListDeployments(cmd.Context(), client, limit)
+	},
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:         "rollback ETAG",
+	Annotations: authNeededAnnotation,
+	Args:        cobra.ExactArgs(1),
+	Short:       "Redeploy the services as they were deployed with the given deployment ID (ETag)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var tail, _ = cmd.Flags().GetBool("tail")
+
+		since := time.Now()
+		etag, err := cli.Rollback(cmd.Context(), client, args[0])
+		if err != nil {
+			return err
+		}
+
+		term.Info(" * Rolling back to", args[0], "with deployment ID", etag)
+
+		if !tail {
+			printDefangHint("To track the rollback, do:", "tail --etag "+etag)
+			return nil
+		}
+
+		term.Info(" * Tailing logs for deployment ID", etag, "; press Ctrl+C to detach:")
+		return cli.Tail(cmd.Context(), client, cli.TailOptions{Etag: etag, Since: since})
+	},
+}
+
 var composeCmd = &cobra.Command{
 	Use:     "compose",
 	Aliases: []string{"stack"},
@@ -693,9 +812,12 @@ var composeUpCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var force, _ = cmd.Flags().GetBool("force")
 		var detach, _ = cmd.Flags().GetBool("detach")
+		var wait, _ = cmd.Flags().GetBool("wait")
+		var waitTimeout, _ = cmd.Flags().GetDuration("wait-timeout")
+		var confirm, _ = cmd.Flags().GetBool("confirm")
 
 		since := time.Now()
-		deploy, err := cli.ComposeStart(cmd.Context(), client, force)
+		deploy, err := cli.ComposeStart(cmd.Context(), client, force, confirm)
 		if err != nil {
 			return err
 		}
@@ -703,6 +825,14 @@ var composeUpCmd = &cobra.Command{
 		printPlaygroundPortalServiceURLs(deploy.Services)
 		printEndpoints(deploy.Services) // TODO: do this at the end
 
+		if wait {
+			var names []string
+			for _, serviceInfo := range deploy.Services {
+				names = append(names, serviceInfo.Service.Name)
+			}
+			return waitForDeployment(cmd.Context(), deploy.Etag, names, waitTimeout, !detach, since)
+		}
+
 		if detach {
 			term.Info(" * Done.")
 			return nil
@@ -715,7 +845,7 @@ var composeUpCmd = &cobra.Command{
 		}
 
 		term.Info(" * Tailing logs for", services, "; press Ctrl+C to detach:")
-		err = cli.Tail(cmd.Context(), client, "", etag, since, false)
+		err = cli.Tail(cmd.Context(), client, cli.TailOptions{Etag: etag, Since: since})
 		if err != nil {
 			return err
 		}
@@ -724,6 +854,39 @@ var composeUpCmd = &cobra.Command{
 	},
 }
 
+// waitForDeployment waits for the services to be deployed, optionally tailing the logs in the meantime
+func waitForDeployment(ctx context.Context, etag types.ETag, names []string, timeout time.Duration, tail bool, since time.Time) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if tail {
+		tailCtx, cancelTail := context.WithCancel(ctx)
+		tailDone := make(chan struct{})
+		defer func() {
+			cancelTail()
+			<-tailDone
+		}()
+
+		term.Info(" * Tailing logs for deployment ID", etag, "until all services are deployed:")
+		go func() {
+			defer close(tailDone)
+			// Errors are ignored; it's the waiter that determines the outcome
+			cli.Tail(tailCtx, client, cli.TailOptions{Etag: etag, Since: since})
+		}()
+	} else {
+		term.Info(" * Waiting for deployment ID", etag, "to complete")
+	}
+
+	if err := cli.WaitServiceState(ctx, client, cli.ServiceStateDeploymentCompleted, etag, names); err != nil {
+		return err
+	}
+	term.Info(" * All services are deployed.")
+	return nil
+}
+
 var composeStartCmd = &cobra.Command{
 	Use:         "start",
 	Aliases:     []string{"deploy"},
@@ -733,7 +896,7 @@ var composeStartCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var force, _ = cmd.Flags().GetBool("force")
 
-		deploy, err := cli.ComposeStart(cmd.Context(), client, force)
+		deploy, err := cli.ComposeStart(cmd.Context(), client, force, false)
 		if err != nil {
 			return err
 		}
@@ -807,7 +970,7 @@ var composeDownCmd = &cobra.Command{
 			return nil
 		}
 
-		err = cli.Tail(cmd.Context(), client, "", etag, since, false)
+		err = cli.Tail(cmd.Context(), client, cli.TailOptions{Etag: etag, Since: since})
 		if err != nil {
 			return err
 		}
@@ -822,12 +985,30 @@ var composeConfigCmd = &cobra.Command{
 	Args:  cobra.NoArgs, // TODO: takes optional list of service names
 	Short: "Reads a Compose file and shows the generated config",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cli.DoDryRun = true // config is like start in a dry run
-		// force=false to calculate the digest
-		if _, err := cli.ComposeStart(cmd.Context(), client, false); !errors.Is(err, cli.ErrDryRun) {
-			return err
-		}
-		return nil
+		cli.DoDryRun = true // config is like start in a dry run, but without the deployed services
+		return cli.ComposeConfig(cmd.Context(), client)
+	},
+}
+
+var composeDiffCmd = &cobra.Command{
+	Use:         "diff",
+	Annotations: authNeededAnnotation,
+	Args:        cobra.NoArgs, // TODO: takes optional list of service names
+	Aliases:     []string{"plan"},
+	Short:       "Reads a Compose file and shows the changes compared to the deployed services",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := cli.ComposeDiff(cmd.Context(), client)
+		return err
+	},
+}
+
+var composeEstimateCmd = &cobra.Command{
+	Use:   "estimate",
+	Args:  cobra.NoArgs, // TODO: takes optional list of service names
+	Short: "Reads a Compose file and estimates the monthly cost of the services",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := cli.ComposeEstimate(cmd.Context(), client)
+		return err
 	},
 }
 
@@ -859,7 +1040,7 @@ var deleteCmd = &cobra.Command{
 		}
 
 		term.Info(" * Tailing logs for update; press Ctrl+C to detach:")
-		return cli.Tail(cmd.Context(), client, "", etag, since, false)
+		return cli.Tail(cmd.Context(), client, cli.TailOptions{Etag: etag, Since: since})
 	},
 }
 
@@ -878,6 +1059,67 @@ var restartCmd = &cobra.Command{
 	},
 }
 
+var execCmd = &cobra.Command{
+	Use:         "exec SERVICE [-- COMMAND...]",
+	Annotations: authNeededAnnotation,
+	Args:        cobra.MinimumNArgs(1),
+	Short:       "Run a command (default: sh) interactively in a running service container",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.Exec(cmd.Context(), client, args[0], args[1:]...)
+	},
+}
+
+var portForwardCmd = &cobra.Command{
+	Use:         "port-forward SERVICE [LOCAL:]REMOTE",
+	Annotations: authNeededAnnotation,
+	Args:        cobra.ExactArgs(2),
+	Aliases:     []string{"forward"},
+	Short:       "Forward a local port to a port of a (private) service",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.PortForward(cmd.Context(), client, args[0], args[1])
+	},
+}
+
+var topCmd = &cobra.Command{
+	Use:         "top",
+	Annotations: authNeededAnnotation,
+	Args:        cobra.NoArgs,
+	Short:       "Show a live dashboard of the services and their logs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var since, _ = cmd.Flags().GetString("since")
+
+		ts, err := cli.ParseTimeOrDuration(since)
+		if err != nil {
+			return fmt.Errorf("invalid duration or time: %w", err)
+		}
+
+		return cli.Top(cmd.Context(), client, ts.UTC())
+	},
+}
+
+var waitCmd = &cobra.Command{
+	Use:         "wait [SERVICE...]",
+	Annotations: authNeededAnnotation,
+	Args:        cobra.ArbitraryArgs,
+	Short:       "Wait for a deployment to complete; exits non-zero if any service fails",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var etag, _ = cmd.Flags().GetString("etag")
+		var timeout, _ = cmd.Flags().GetDuration("timeout")
+		var tail, _ = cmd.Flags().GetBool("tail")
+
+		names := args
+		if len(names) == 0 {
+			project, err := client.LoadProject()
+			if err != nil {
+				return err
+			}
+			names = project.ServiceNames()
+		}
+
+		return waitForDeployment(cmd.Context(), etag, names, timeout, tail, time.Now())
+	},
+}
+
 var sendCmd = &cobra.Command{
 	Use:         "send",
 	Hidden:      true, // not available in private beta
@@ -960,12 +1202,84 @@ var bootstrapRefreshCmd = &cobra.Command{
 var bootstrapCancelCmd = &cobra.Command{
 	Use:   "cancel",
 	Args:  cobra.NoArgs, // TODO: set MaximumNArgs(1),
-	Short: "Cancel the current CD operation",
+	Short: "Cancel the current CD operation and release the CD lock",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cli.BootstrapCommand(cmd.Context(), client, "cancel")
 	},
 }
 
+var bootstrapMigrateSecretsCmd = &cobra.Command{
+	Use:   "migrate-secrets",
+	Args:  cobra.NoArgs,
+	Short: "Re-encrypt the stack secrets with the KMS key instead of the passphrase",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.BootstrapCommand(cmd.Context(), client, "change-secrets-provider")
+	},
+}
+
+var bootstrapTasksCmd = &cobra.Command{
+	Use:     "ps",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"tasks"},
+	Short:   "List the running and recent CD tasks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.BootstrapTasks(cmd.Context(), client)
+	},
+}
+
+var bootstrapLogsCmd = &cobra.Command{
+	Use:   "logs [TASK]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Show the logs of a CD task; defaults to the most recent one",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var taskID string
+		if len(args) > 0 {
+			taskID = args[0]
+		}
+		exportDir, _ := cmd.Flags().GetString("export")
+
+		return cli.BootstrapLogs(cmd.Context(), client, taskID, exportDir)
+	},
+}
+
+var bootstrapUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Args:  cobra.NoArgs,
+	Short: "Remove the CD lock, if a CD task was killed without releasing it",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.BootstrapUnlock(cmd.Context(), client)
+	},
+}
+
+var bootstrapSetUpCmd = &cobra.Command{
+	Use:   "setup",
+	Args:  cobra.NoArgs,
+	Short: "Create or update the CD stack, after printing the changes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		preview, _ := cmd.Flags().GetBool("preview")
+
+		return cli.BootstrapSetUp(cmd.Context(), client, preview)
+	},
+}
+
+var bootstrapDriftCmd = &cobra.Command{
+	Use:   "drift",
+	Args:  cobra.NoArgs,
+	Short: "Detect changes to the CD stack that were made outside of Defang",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.BootstrapDrift(cmd.Context(), client)
+	},
+}
+
+var bootstrapPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Args:  cobra.NoArgs,
+	Short: "Print the IAM policy of the CD task role",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.BootstrapPolicy()
+	},
+}
+
 var bootstrapTearDownCmd = &cobra.Command{
 	Use:   "teardown",
 	Args:  cobra.NoArgs,
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/defang-io/defang/src/pkg/clouds/aws"
 	"github.com/defang-io/defang/src/pkg/cmd"
 	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
 	"github.com/spf13/pflag"
 )
 
@@ -22,12 +24,22 @@ var (
 	platform = runFlags.String("platform", "", "Set platform if host is multi-platform capable")
 	vpcid    = runFlags.String("vpcid", "", "VPC to use for the task")
 	subnetid = runFlags.String("subnetid", "", "Subnet to use for the task")
+	ports    = runFlags.StringArrayP("port", "p", nil, `Container port to open, like "8080" or "53/udp"`)
+	ingress  = runFlags.StringArray("allow-from", nil, `CIDR that can connect to the container ports, or "myip"; none by default`)
+	egress   = runFlags.StringArray("egress", nil, "CIDR the task can connect to; all by default")
+	gpus     = runFlags.Uint32("gpus", 0, "Number of NVIDIA GPUs; runs on an EC2 GPU instance instead of Fargate")
+	spot     = runFlags.Bool("spot", true, "Run on Spot capacity; falls back to on-demand if there's no Spot capacity")
+	file     = runFlags.StringP("file", "f", "", "Compose file with the main service and its helpers, instead of an image")
 	// driver = pflag.StringP("driver", "d", "auto", "Container runner to use. Choices are: pulumi-ecs, docker")
 
 	version = "development" // overwritten by build script -ldflags "-X main.version=..."
 )
 
 func init() {
+	for _, flags := range []*pflag.FlagSet{pflag.CommandLine, runFlags} {
+		flags.StringVar(&aws.RoleARN, "aws-role-arn", aws.RoleARN, "AWS role to assume, for using another account")
+		flags.StringVar(&aws.ExternalID, "aws-external-id", aws.ExternalID, "External ID to use when assuming the AWS role")
+	}
 	runFlags.StringVarP(region, "region", "r", os.Getenv("AWS_REGION"), "Which cloud region to use, or blank for local Docker")
 }
 
@@ -38,7 +50,9 @@ func usage() {
 	fmt.Println(`
 Commands:
   run <image> [arg...]   Create and run a new task from an image
+  run -f <file> [arg...] Create and run a new task from the services of a Compose file
   logs <task ID>         Fetch the logs of a task
+  exec <task ID> [cmd]   Run a command (default: sh) interactively in a task
   stop <task ID>         Stop a running task
   info <task ID>         Show information about a task
   destroy                Destroy all resources created by this tool`)
@@ -78,8 +92,12 @@ func main() {
 	case "help", "":
 		usage()
 	case "run", "r":
-		if runFlags.NArg() < 1 {
-			term.Fatal("run requires an image name (and optional arguments)")
+		image, runArgs := "", runFlags.Args()
+		if *file == "" {
+			if runFlags.NArg() < 1 {
+				term.Fatal("run requires an image name or a Compose file (and optional arguments)")
+			}
+			image, runArgs = runArgs[0], runArgs[1:]
 		}
 
 		envMap := make(map[string]string)
@@ -96,16 +114,32 @@ func main() {
 			}
 		}
 
+		var containerPorts []types.Port
+		for _, port := range *ports {
+			p, err := cmd.ParsePort(port)
+			if err != nil {
+				term.Fatal(err)
+			}
+			containerPorts = append(containerPorts, p)
+		}
+
 		memory := cmd.ParseMemory(*memory)
 		err = cmd.Run(ctx, cmd.RunContainerArgs{
 			Region:   region,
-			Image:    runFlags.Arg(0),
+			Image:    image,
 			Memory:   memory,
-			Args:     runFlags.Args()[1:],
+			Args:     runArgs,
 			Env:      envMap,
 			Platform: *platform,
 			VpcID:    *vpcid,
 			SubnetID: *subnetid,
+			Ports:    containerPorts,
+			Ingress:  *ingress,
+			Egress:   *egress,
+			Spot:     *spot,
+			Gpus:     *gpus,
+
+			ComposeFile: *file,
 		})
 	case "stop", "s":
 		taskID := requireTaskID()
@@ -113,6 +147,12 @@ func main() {
 	case "logs", "tail", "l":
 		taskID := requireTaskID()
 		err = cmd.Logs(ctx, region, &taskID)
+	case "exec", "e":
+		if pflag.NArg() < 2 {
+			term.Fatal(command + " requires a task ID argument")
+		}
+		taskID := pflag.Arg(1)
+		err = cmd.Exec(ctx, region, &taskID, pflag.Args()[2:]...)
 	case "destroy", "teardown", "d":
 		if pflag.NArg() != 1 {
 			term.Fatal("destroy does not take any arguments")
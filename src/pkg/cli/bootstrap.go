@@ -2,10 +2,19 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/cli/client/byoc/aws"
 	"github.com/defang-io/defang/src/pkg/term"
 )
 
@@ -24,7 +33,7 @@ func BootstrapCommand(ctx context.Context, client client.Client, command string)
 	if err != nil || etag == "" {
 		return err
 	}
-	return Tail(ctx, client, "", etag, since, false)
+	return Tail(ctx, client, TailOptions{Etag: etag, Since: since})
 }
 
 func BootstrapList(ctx context.Context, client client.Client) error {
@@ -36,8 +45,228 @@ func BootstrapList(ctx context.Context, client client.Client) error {
 	if err != nil {
 		return err
 	}
-	for _, stack := range stacks {
-		fmt.Println(" -", stack)
+	for _, project := range groupStacks(stacks) {
+		fmt.Println(" -", orDash(project.Name))
+		for _, stack := range project.Stacks {
+			fmt.Println("   -", stack)
+		}
 	}
 	return nil
 }
+
+type projectStacks struct {
+	Name   string
+	Stacks []string
+}
+
+// groupStacks groups the "project/stack" names by project, sorted by name
+func groupStacks(stacks []string) []projectStacks {
+	byProject := make(map[string][]string)
+	for _, qualified := range stacks {
+		project, stack, ok := strings.Cut(qualified, "/")
+		if !ok {
+			project, stack = "", qualified // stacks that are not scoped to a project
+		}
+		byProject[project] = append(byProject[project], stack)
+	}
+
+	var projects []projectStacks
+	for project, stacks := range byProject {
+		sort.Strings(stacks)
+		projects = append(projects, projectStacks{Name: project, Stacks: stacks})
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects
+}
+
+func BootstrapTasks(ctx context.Context, client client.Client) error {
+	term.Debug(" - Listing CD tasks")
+	if DoDryRun {
+		return ErrDryRun
+	}
+	tasks, err := client.BootstrapTasks(ctx)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		term.Info(" * No running or recent CD tasks")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(term.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tPROJECT\tCOMMAND\tETAG\tSTATUS\tSTARTED\tBY")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", task.ID, orDash(task.Project), orDash(task.Command), orDash(task.Etag), orDash(task.Status), task.StartedAt.Local().Format(time.DateTime), orDash(task.StartedBy))
+	}
+	return w.Flush()
+}
+
+// BootstrapLogs shows the logs of the given CD task, or of the most recent one if taskID is empty; with exportDir, it saves them instead
+func BootstrapLogs(ctx context.Context, client client.Client, taskID, exportDir string) error {
+	term.Debug(" - Showing logs of CD task", taskID)
+	if DoDryRun {
+		return ErrDryRun
+	}
+
+	var since time.Time
+	if taskID == "" || exportDir == "" {
+		// Exported logs are kept longer than the tasks, so only look for the task if needed
+		tasks, err := client.BootstrapTasks(ctx)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if taskID == "" || task.ID == taskID {
+				taskID = task.ID
+				since = task.StartedAt
+				break
+			}
+		}
+	}
+	if taskID == "" {
+		return errors.New("no running or recent CD tasks")
+	}
+
+	if exportDir != "" {
+		events, err := client.BootstrapQueryLogs(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return fmt.Errorf("no logs found for CD task %s", taskID)
+		}
+		files, err := exportLogs(exportDir, events)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			term.Info(" * Exported", file)
+		}
+		return nil
+	}
+
+	if since.IsZero() {
+		since = time.Now().Add(-time.Hour) // stopped tasks are only kept for about an hour
+	}
+
+	// Tail treats an etag that is not a deployment ID as a task ID
+	return Tail(ctx, client, TailOptions{Etag: taskID, Since: since})
+}
+
+// exportLogs writes the log events to one file per log stream in dir and returns the file names
+func exportLogs(dir string, events []client.CdLogEvent) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	byStream := make(map[string]*os.File)
+	defer func() {
+		for _, f := range byStream {
+			f.Close()
+		}
+	}()
+	for _, event := range events {
+		f, ok := byStream[event.Stream]
+		if !ok {
+			name := filepath.Join(dir, strings.ReplaceAll(event.Stream, "/", "_")+".log")
+			var err error
+			if f, err = os.Create(name); err != nil {
+				return nil, err
+			}
+			byStream[event.Stream] = f
+			files = append(files, name)
+		}
+		if _, err := fmt.Fprintln(f, event.Timestamp.UTC().Format(time.RFC3339Nano), event.Message); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// BootstrapUnlock removes the CD lock, for when a CD task was killed without releasing it
+func BootstrapUnlock(ctx context.Context, client client.Client) error {
+	term.Debug(" - Removing the CD lock")
+	if DoDryRun {
+		return ErrDryRun
+	}
+	if err := client.BootstrapUnlock(ctx); err != nil {
+		return err
+	}
+	term.Info(" * Removed the CD lock")
+	return nil
+}
+
+// BootstrapPolicy prints the IAM policy of the CD task role, for creating the role with --cd-role-arn or a permissions boundary
+func BootstrapPolicy() error {
+	policy, err := json.MarshalIndent(aws.CdTaskPolicy(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(term.Stdout, string(policy))
+	return err
+}
+
+// BootstrapSetUp creates or updates the CD stack with the changes it prints; with preview, it only prints them
+func BootstrapSetUp(ctx context.Context, client client.Client, preview bool) error {
+	term.Debug(" - Previewing the CD stack changes")
+	plan, err := client.BootstrapPreview(ctx)
+	if err != nil {
+		return err
+	}
+	if plan.ID == "" {
+		term.Info(" * The CD stack is up to date")
+		return nil
+	}
+	term.Info(" * The CD stack will be changed:")
+	if err := printStackChanges(term.Stdout, plan.Changes); err != nil {
+		return err
+	}
+	if preview || DoDryRun {
+		// Use a new context in case ctx was canceled
+		if err := client.BootstrapDiscard(context.Background(), plan); err != nil {
+			term.Debug(" - Failed to discard the CD stack changes:", err)
+		}
+		if preview {
+			return nil
+		}
+		return ErrDryRun
+	}
+
+	term.Debug(" - Setting up the CD stack")
+	if err := client.BootstrapSetUp(ctx, plan); err != nil {
+		return err
+	}
+	term.Info(" * The CD stack was updated")
+	return nil
+}
+
+func printStackChanges(w io.Writer, changes []client.CdStackChange) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tRESOURCE\tTYPE\tREPLACEMENT")
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", change.Action, change.Resource, change.Type, orDash(change.Replacement))
+	}
+	return tw.Flush()
+}
+
+// BootstrapDrift prints the resources of the CD stack that were changed outside of the CD setup
+func BootstrapDrift(ctx context.Context, client client.Client) error {
+	term.Debug(" - Detecting drift of the CD stack")
+	drifts, err := client.BootstrapDrift(ctx)
+	if err != nil {
+		return err
+	}
+	if len(drifts) == 0 {
+		term.Info(" * No drift detected in the CD stack")
+		return nil
+	}
+
+	term.Warn(" ! The CD stack has drifted; run 'defang cd setup' to restore it")
+	tw := tabwriter.NewWriter(term.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE\tTYPE\tSTATUS\tPROPERTIES")
+	for _, drift := range drifts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", drift.Resource, drift.Type, drift.Status, orDash(strings.Join(drift.Properties, ",")))
+	}
+	return tw.Flush()
+}
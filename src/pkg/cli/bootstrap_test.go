@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+)
+
+func TestGroupStacks(t *testing.T) {
+	tests := []struct {
+		name   string
+		stacks []string
+		want   []projectStacks
+	}{
+		{"empty", nil, nil},
+		{"single", []string{"app/beta"}, []projectStacks{{"app", []string{"beta"}}}},
+		{"multiple", []string{"web/prod", "app/staging", "app/beta", "app-two/beta"}, []projectStacks{
+			{"app", []string{"beta", "staging"}},
+			{"app-two", []string{"beta"}},
+			{"web", []string{"prod"}},
+		}},
+		{"unscoped", []string{"beta", "app/beta"}, []projectStacks{
+			{"", []string{"beta"}},
+			{"app", []string{"beta"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupStacks(tt.stacks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupStacks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintStackChanges(t *testing.T) {
+	var buf bytes.Buffer
+	err := printStackChanges(&buf, []client.CdStackChange{
+		{Action: "Modify", Resource: "TaskDefinition", Type: "AWS::ECS::TaskDefinition", Replacement: "True"},
+		{Action: "Add", Resource: "SecretsKey", Type: "AWS::KMS::Key"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `ACTION  RESOURCE        TYPE                      REPLACEMENT
+Modify  TaskDefinition  AWS::ECS::TaskDefinition  True
+Add     SecretsKey      AWS::KMS::Key             -
+`
+	if got := buf.String(); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+type planClient struct {
+	client.Client
+	plan      *client.CdStackPlan
+	applied   string
+	discarded string
+}
+
+func (p *planClient) BootstrapPreview(context.Context) (*client.CdStackPlan, error) {
+	return p.plan, nil
+}
+
+func (p *planClient) BootstrapSetUp(_ context.Context, plan *client.CdStackPlan) error {
+	p.applied = plan.ID
+	return nil
+}
+
+func (p *planClient) BootstrapDiscard(_ context.Context, plan *client.CdStackPlan) error {
+	p.discarded = plan.ID
+	return nil
+}
+
+func TestBootstrapSetUp(t *testing.T) {
+	changes := []client.CdStackChange{{Action: "Add", Resource: "SecretsKey", Type: "AWS::KMS::Key"}}
+	tests := []struct {
+		name          string
+		plan          *client.CdStackPlan
+		preview       bool
+		wantApplied   string
+		wantDiscarded string
+	}{
+		{"up to date", &client.CdStackPlan{}, false, "", ""},
+		{"apply", &client.CdStackPlan{ID: "cs-1", Changes: changes}, false, "cs-1", ""},
+		{"preview", &client.CdStackPlan{ID: "cs-2", Changes: changes}, true, "", "cs-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &planClient{plan: tt.plan}
+			if err := BootstrapSetUp(context.Background(), c, tt.preview); err != nil {
+				t.Fatal(err)
+			}
+			if c.applied != tt.wantApplied {
+				t.Errorf("expected change set %q to be applied, got %q", tt.wantApplied, c.applied)
+			}
+			if c.discarded != tt.wantDiscarded {
+				t.Errorf("expected change set %q to be discarded, got %q", tt.wantDiscarded, c.discarded)
+			}
+		})
+	}
+}
+
+func TestExportLogs(t *testing.T) {
+	dir := t.TempDir()
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	files, err := exportLogs(dir, []client.CdLogEvent{
+		{Stream: "crun/main/abc", Timestamp: ts, Message: "Updating (beta)"},
+		{Stream: "crun/defang-cd/abc", Timestamp: ts, Message: "copying"},
+		{Stream: "crun/main/abc", Timestamp: ts.Add(time.Second), Message: "done"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "crun_main_abc.log"), filepath.Join(dir, "crun_defang-cd_abc.log")}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("expected files %v, got %v", want, files)
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(content); got != "2024-05-01T12:00:00Z Updating (beta)\n2024-05-01T12:00:01Z done\n" {
+		t.Errorf("unexpected content %q", got)
+	}
+}
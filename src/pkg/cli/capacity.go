@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	compose "github.com/compose-spec/compose-go/v2/types"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+const (
+	capacitySpot     = "spot"
+	capacityOnDemand = "on-demand"
+)
+
+// getCapacity returns the capacity from x-defang-capacity, which is either "spot", "on-demand", or a mapping with the
+// weights of both, like {spot: 3, on-demand: 1}; nil if not set, so the provider's default is used.
+func getCapacity(svccfg compose.ServiceConfig) (*defangv1.Capacity, error) {
+	switch val := svccfg.Extensions["x-defang-capacity"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		switch val {
+		case capacitySpot:
+			return &defangv1.Capacity{Spot: 1}, nil
+		case capacityOnDemand:
+			return &defangv1.Capacity{OnDemand: 1}, nil
+		}
+		return nil, fmt.Errorf("x-defang-capacity must be %q, %q, or a mapping with their weights", capacitySpot, capacityOnDemand)
+	case map[string]any:
+		capacity := &defangv1.Capacity{}
+		for key, value := range val {
+			weight, ok := capacityWeight(value)
+			if !ok {
+				return nil, fmt.Errorf("x-defang-capacity weight of %q must be a non-negative integer", key)
+			}
+			switch key {
+			case capacitySpot:
+				capacity.Spot = weight
+			case capacityOnDemand:
+				capacity.OnDemand = weight
+			default:
+				return nil, fmt.Errorf("x-defang-capacity has unsupported key %q; must be %q or %q", key, capacitySpot, capacityOnDemand)
+			}
+		}
+		if capacity.Spot == 0 && capacity.OnDemand == 0 {
+			return nil, errors.New("x-defang-capacity must have a positive weight for spot or on-demand")
+		}
+		return capacity, nil
+	default:
+		return nil, errors.New("x-defang-capacity must be a string or a mapping")
+	}
+}
+
+// capacityWeight converts a YAML number to a weight; the loader can return any of these types
+func capacityWeight(value any) (uint32, bool) {
+	var weight float64
+	switch v := value.(type) {
+	case int:
+		weight = float64(v)
+	case int64:
+		weight = float64(v)
+	case uint64:
+		weight = float64(v)
+	case float64:
+		weight = v
+	default:
+		return 0, false
+	}
+	if weight < 0 || weight > 1000 || weight != float64(uint32(weight)) {
+		return 0, false // ECS allows weights from 0 to 1000
+	}
+	return uint32(weight), true
+}
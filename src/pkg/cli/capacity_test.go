@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+
+	compose "github.com/compose-spec/compose-go/v2/types"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGetCapacity(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    *defangv1.Capacity
+		wantErr bool
+	}{
+		{"unset", nil, nil, false},
+		{"spot", "spot", &defangv1.Capacity{Spot: 1}, false},
+		{"on-demand", "on-demand", &defangv1.Capacity{OnDemand: 1}, false},
+		{"mixed", map[string]any{"spot": 3, "on-demand": 1}, &defangv1.Capacity{Spot: 3, OnDemand: 1}, false},
+		{"float weight", map[string]any{"spot": 2.0}, &defangv1.Capacity{Spot: 2}, false},
+		{"unknown string", "reserved", nil, true},
+		{"unknown key", map[string]any{"spot": 1, "reserved": 1}, nil, true},
+		{"negative weight", map[string]any{"spot": -1}, nil, true},
+		{"fractional weight", map[string]any{"spot": 0.5}, nil, true},
+		{"zero weights", map[string]any{"spot": 0, "on-demand": 0}, nil, true},
+		{"list", []any{"spot"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svccfg := compose.ServiceConfig{Extensions: map[string]any{}}
+			if tt.value != nil {
+				svccfg.Extensions["x-defang-capacity"] = tt.value
+			}
+			got, err := getCapacity(svccfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -9,12 +9,15 @@ import (
 	"io"
 	"net"
 	"os"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -27,6 +30,7 @@ import (
 	"github.com/defang-io/defang/src/pkg/clouds/aws"
 	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
 	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn/outputs"
 	"github.com/defang-io/defang/src/pkg/http"
 	"github.com/defang-io/defang/src/pkg/quota"
 	"github.com/defang-io/defang/src/pkg/term"
@@ -45,8 +49,10 @@ type ByocAws struct {
 	privateLbIps            []string
 	publicNatIps            []string
 	pulumiProject           string
+	projectQuotas           quota.Quotas // from x-defang-quotas
 	pulumiStack             string
 	quota                   quota.Quotas
+	region                  aws.Region // region of the services; defaults to the CD region
 	setupDone               bool
 	tenantID                string
 	shouldDelegateSubdomain bool
@@ -59,11 +65,11 @@ func NewByoc(tenantId types.TenantID, defClient *client.GrpcClient) *ByocAws {
 		GrpcClient:    defClient,
 		cdTasks:       make(map[string]ecs.TaskArn),
 		customDomain:  "",
-		driver:        cfn.New(CdTaskPrefix, aws.Region("")), // default region
-		pulumiProject: os.Getenv("COMPOSE_PROJECT_NAME"),     // overrides the project name, except in the playground env
-		pulumiStack:   "beta",                                // TODO: make customizable
+		driver:        newCdDriver(),
+		pulumiProject: os.Getenv("COMPOSE_PROJECT_NAME"), // overrides the project name, except in the playground env
+		pulumiStack:   Stack,
 		quota: quota.Quotas{
-			// These serve mostly to pevent fat-finger errors in the CLI or Compose files
+			// Defaults that serve mostly to prevent fat-finger errors; see loadQuotas
 			Cpus:       16,
 			Gpus:       8,
 			MemoryMiB:  65536,
@@ -71,6 +77,7 @@ func NewByoc(tenantId types.TenantID, defClient *client.GrpcClient) *ByocAws {
 			Services:   40,
 			ShmSizeMiB: 30720,
 		},
+		region:   aws.Region(Region),
 		tenantID: string(tenantId),
 		// privateLbIps:  nil,                                                 // TODO: grab these from the AWS API or outputs
 		// publicNatIps:  nil,                                                 // TODO: grab these from the AWS API or outputs
@@ -82,6 +89,9 @@ func (b *ByocAws) LoadProject() (*compose.Project, error) {
 	if b.privateDomain != "" {
 		panic("LoadProject should only be called once")
 	}
+	if err := b.validateStack(); err != nil {
+		return nil, err
+	}
 	var proj *compose.Project
 	var err error
 	if b.pulumiProject != "" {
@@ -92,17 +102,120 @@ func (b *ByocAws) LoadProject() (*compose.Project, error) {
 	if err != nil {
 		return nil, err
 	}
+	if ext, ok := proj.Extensions["x-defang-quotas"]; ok {
+		if b.projectQuotas, err = parseProjectQuotas(ext); err != nil {
+			return nil, fmt.Errorf("x-defang-quotas: %w", err)
+		}
+	}
 	b.privateDomain = dnsSafeLabel(proj.Name) + ".internal"
 	b.pulumiProject = proj.Name
 	return proj, nil
 }
 
-func (b *ByocAws) setUp(ctx context.Context) error {
-	if b.setupDone {
-		return nil
+func newCdDriver() *cfn.AwsEcs {
+	driver := cfn.New(CdTaskPrefix, aws.Region(CdRegion))          // empty for the default region
+	driver.Overrides.EgressCIDRs = pkg.SplitByComma(CdEgressCIDRs) // the CD task has no ports, so no ingress
+	driver.Overrides.LogDestinationARN = CdLogDestinationARN
+	driver.Overrides.LogDestinationRoleARN = CdLogDestinationRoleARN
+	driver.Overrides.LogRetentionDays = CdLogRetentionDays
+	driver.Overrides.PermissionsBoundaryARN = CdPermissionsBoundary
+	driver.Overrides.TaskRoleARN = CdRoleARN
+	driver.Overrides.TaskRolePolicy = CdTaskPolicy()
+	return driver
+}
+
+// prepareCdDriver validates the options of the CD stack and applies them to the driver
+func (b *ByocAws) prepareCdDriver(ctx context.Context) error {
+	switch SecretsProvider {
+	case SecretsProviderKms:
+	case SecretsProviderPassphrase:
+		if os.Getenv("PULUMI_CONFIG_PASSPHRASE") == "" {
+			return errors.New("the passphrase secrets provider requires PULUMI_CONFIG_PASSPHRASE")
+		}
+	default:
+		return fmt.Errorf("unsupported secrets provider %q; must be %q or %q", SecretsProvider, SecretsProviderKms, SecretsProviderPassphrase)
+	}
+	b.driver.Overrides.CreateSecretsKey = SecretsProvider == SecretsProviderKms
+
+	// Keep the settings of the existing CD stack, so the options are only needed to change them
+	stackOutputs, err := b.driver.StackOutputs(ctx)
+	if err != nil {
+		return annotateAwsError(err)
+	}
+	keepCdStackSettings(&b.driver.Overrides, stackOutputs)
+	if b.driver.Spot, err = cdSpot(CdSpot, stackOutputs[outputs.Spot]); err != nil {
+		return err
+	}
+
+	if b.driver.Overrides.LogRetentionDays != 0 && !slices.Contains(validLogRetentionDays, b.driver.Overrides.LogRetentionDays) {
+		return fmt.Errorf("unsupported log retention of %d days; must be one of %v", b.driver.Overrides.LogRetentionDays, validLogRetentionDays)
+	}
+	if VpcID != "" || SubnetIDs != "" {
+		var subnetID string
+		for _, id := range strings.Split(SubnetIDs, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				b.driver.SubnetIDs = append(b.driver.SubnetIDs, id)
+			}
+		}
+		if len(b.driver.SubnetIDs) > 0 {
+			subnetID = b.driver.SubnetIDs[0]
+		}
+		b.driver.PrivateSubnet = true // no public IPs in an existing VPC
+		if err := b.driver.PopulateVPCandSubnetID(ctx, VpcID, subnetID); err != nil {
+			return annotateAwsError(err)
+		}
+		if b.serviceRegion() != b.driver.Region {
+			return errors.New("an existing VPC can only be used if the services are in the CD region")
+		}
+	}
+	return nil
+}
+
+// keepCdStackSettings copies the settings of the existing CD stack from its outputs to the overrides that weren't given
+func keepCdStackSettings(overrides *cfn.TemplateOverrides, stackOutputs map[string]string) {
+	if stackOutputs[outputs.SecretsKeyARN] != "" {
+		overrides.CreateSecretsKey = true // the key can't be removed without losing the secrets it encrypted
+	}
+	if overrides.LogRetentionDays == 0 {
+		overrides.LogRetentionDays, _ = strconv.Atoi(stackOutputs[outputs.LogRetentionDays]) // 0 for the default
+	}
+	if overrides.LogDestinationARN == "" {
+		overrides.LogDestinationARN = stackOutputs[outputs.LogDestinationARN]
+		if overrides.LogDestinationRoleARN == "" {
+			overrides.LogDestinationRoleARN = stackOutputs[outputs.LogDestinationRole]
+		}
+	}
+	if len(overrides.EgressCIDRs) == 0 {
+		overrides.EgressCIDRs = pkg.SplitByComma(stackOutputs[outputs.EgressCIDRs])
+	}
+	if overrides.TaskRoleARN == "" {
+		overrides.TaskRoleARN = stackOutputs[outputs.TaskRoleARN]
+	}
+	if overrides.PermissionsBoundaryARN == "" {
+		overrides.PermissionsBoundaryARN = stackOutputs[outputs.PermissionsBoundary]
+	}
+}
+
+// cdSpot returns whether the CD task runs on Spot: the given setting, or else the one of the existing CD stack, or else true.
+// Run falls back to on-demand if there's no Spot capacity.
+func cdSpot(setting, stackSetting string) (bool, error) {
+	if setting == "" {
+		setting = stackSetting
+	}
+	if setting == "" {
+		return true, nil
 	}
+	spot, err := strconv.ParseBool(setting)
+	if err != nil {
+		return false, fmt.Errorf("invalid CD spot setting %q; must be true or false", setting)
+	}
+	return spot, nil
+}
+
+// cdContainers returns the containers of the CD task
+func cdContainers() []types.Container {
 	cdTaskName := CdTaskPrefix
-	containers := []types.Container{
+	return []types.Container{
 		{
 			Image:     "public.ecr.aws/pulumi/pulumi-nodejs:latest",
 			Name:      ecs.ContainerName,
@@ -134,9 +247,23 @@ func (b *ByocAws) setUp(ctx context.Context) error {
 			},
 		},
 	}
-	if err := b.driver.SetUp(ctx, containers); err != nil {
+}
+
+func (b *ByocAws) setUp(ctx context.Context) error {
+	if b.setupDone {
+		return nil
+	}
+	if err := b.prepareCdDriver(ctx); err != nil {
+		return err
+	}
+	// Only an explicit 'cd setup' changes an existing CD stack, after previewing the changes
+	outdated, err := b.driver.CreateOrFill(ctx, cdContainers())
+	if err != nil {
 		return annotateAwsError(err)
 	}
+	if outdated {
+		term.Warn(" ! The CD stack is out of date; run 'defang cd setup' to review and apply the changes")
+	}
 
 	if b.customDomain == "" {
 		domain, err := b.GetDelegateSubdomainZone(ctx)
@@ -157,10 +284,16 @@ func (b *ByocAws) Deploy(ctx context.Context, req *defangv1.DeployRequest) (*def
 		return nil, err
 	}
 
-	etag := pkg.RandomID()
-	if len(req.Services) > b.quota.Services {
-		return nil, errors.New("maximum number of services reached")
+	// Moving the services to another region would replace all of them; make sure that's intended
+	if _, err := b.GetServices(ctx); err != nil && connect.CodeOf(err) == connect.CodeFailedPrecondition {
+		return nil, err
 	}
+
+	if err := b.validateServices(ctx, req.Services); err != nil {
+		return nil, err
+	}
+
+	etag := pkg.RandomID()
 	serviceInfos := []*defangv1.ServiceInfo{}
 	for _, service := range req.Services {
 		serviceInfo, err := b.update(ctx, service)
@@ -183,7 +316,9 @@ func (b *ByocAws) Deploy(ctx context.Context, req *defangv1.DeployRequest) (*def
 	}
 
 	data, err := proto.Marshal(&defangv1.ListServicesResponse{
-		Services: serviceInfos,
+		Services:   serviceInfos,
+		DeployedBy: os.Getenv("USER"), // same as the StartedBy tag of the CD task
+		Region:     b.serviceRegion().String(),
 	})
 	if err != nil {
 		return nil, err
@@ -218,7 +353,7 @@ func (b *ByocAws) Deploy(ctx context.Context, req *defangv1.DeployRequest) (*def
 			return nil, err
 		}
 	}
-	taskArn, err := b.runCdCommand(ctx, "up", payloadString)
+	taskArn, err := b.runCdCommand(ctx, etag, "up", payloadString)
 	if err != nil {
 		return nil, err
 	}
@@ -244,7 +379,9 @@ func (b ByocAws) findZone(ctx context.Context, domain, role string) (string, err
 
 	if role != "" {
 		stsClient := sts.NewFromConfig(cfg)
-		creds := stscreds.NewAssumeRoleProvider(stsClient, role)
+		creds := stscreds.NewAssumeRoleProvider(stsClient, role, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = aws.RoleSessionName()
+		})
 		cfg.Credentials = awssdk.NewCredentialsCache(creds)
 	}
 
@@ -321,7 +458,7 @@ func (b ByocAws) WhoAmI(ctx context.Context) (*defangv1.WhoAmIResponse, error) {
 	}
 	return &defangv1.WhoAmIResponse{
 		Tenant:  b.tenantID,
-		Region:  cfg.Region,
+		Region:  b.serviceRegion().String(),
 		Account: *identity.Account,
 	}, nil
 }
@@ -352,24 +489,56 @@ func (b *ByocAws) environment() map[string]string {
 	if b.pulumiProject == "" {
 		panic("pulumiProject not set")
 	}
-	region := b.driver.Region // TODO: this should be the destination region, not the CD region; make customizable
 	return map[string]string{
-		// "AWS_REGION":               region.String(), should be set by ECS (because of CD task role)
+		"AWS_REGION":                 b.serviceRegion().String(), // the CD task runs in the CD region, but deploys to this region
 		"DEFANG_PREFIX":              DefangPrefix,
 		"DEFANG_DEBUG":               os.Getenv("DEFANG_DEBUG"), // TODO: use the global DoDebug flag
 		"DEFANG_ORG":                 b.tenantID,
 		"DOMAIN":                     b.customDomain,
 		"PRIVATE_DOMAIN":             b.privateDomain,
 		"PROJECT":                    b.pulumiProject,
-		"PULUMI_BACKEND_URL":         fmt.Sprintf(`s3://%s?region=%s&awssdk=v2`, b.bucketName(), region),
-		"PULUMI_CONFIG_PASSPHRASE":   pkg.Getenv("PULUMI_CONFIG_PASSPHRASE", "asdf"), // TODO: make customizable
+		"PULUMI_BACKEND_URL":         fmt.Sprintf(`s3://%s?region=%s&awssdk=v2`, b.bucketName(), b.driver.Region),
+		"PULUMI_CONFIG_PASSPHRASE":   pkg.Getenv("PULUMI_CONFIG_PASSPHRASE", legacyPassphrase), // to decrypt stacks that haven't been migrated yet
+		"SECRETS_PROVIDER":           b.secretsProvider(),
 		"STACK":                      b.pulumiStack,
+		"SUBNET_IDS":                 strings.Join(b.driver.SubnetIDs, ","), // private subnets for the services; empty to use all private subnets of VPC_ID
+		"VPC_ID":                     b.driver.VpcID,                        // empty to create a VPC for the services
 		"NPM_CONFIG_UPDATE_NOTIFIER": "false",
 		"PULUMI_SKIP_UPDATE_CHECK":   "true",
 	}
 }
 
-func (b *ByocAws) runCdCommand(ctx context.Context, cmd ...string) (ecs.TaskArn, error) {
+// secretsProvider returns the Pulumi secrets provider for new stacks and for migrating existing ones
+func (b *ByocAws) secretsProvider() string {
+	if SecretsProvider == SecretsProviderPassphrase || b.driver.SecretsKeyARN == "" {
+		return SecretsProviderPassphrase
+	}
+	return "awskms:///" + b.driver.SecretsKeyARN // the ARN includes the region
+}
+
+// runCdCommand acquires the CD lock and starts the CD task; the etag, if any, is added as a tag so the task can be found later
+func (b *ByocAws) runCdCommand(ctx context.Context, etag types.ETag, cmd ...string) (ecs.TaskArn, error) {
+	lock := cdLock{
+		ID:        pkg.RandomID(),
+		Command:   cmd[0],
+		Etag:      etag,
+		StartedAt: time.Now(),
+		StartedBy: os.Getenv("USER"),
+	}
+	versionID, err := b.acquireCdLock(ctx, lock)
+	if err != nil {
+		return nil, err
+	}
+	taskArn, err := b.startCdTask(ctx, etag, lock.ID, cmd...)
+	if err != nil {
+		b.releaseCdLock(ctx, versionID)
+		return nil, err
+	}
+	return taskArn, nil
+}
+
+// startCdTask starts the CD task without acquiring the CD lock; the lock ID, if any, is added as a tag so a stale lock can be detected
+func (b *ByocAws) startCdTask(ctx context.Context, etag types.ETag, lockID string, cmd ...string) (ecs.TaskArn, error) {
 	env := b.environment()
 	if term.DoDebug {
 		debugEnv := " -"
@@ -378,7 +547,14 @@ func (b *ByocAws) runCdCommand(ctx context.Context, cmd ...string) (ecs.TaskArn,
 		}
 		term.Debug(debugEnv, "npm run dev", strings.Join(cmd, " "))
 	}
-	return b.driver.Run(ctx, env, cmd...)
+	tags := map[string]string{tagProject: b.pulumiProject}
+	if etag != "" {
+		tags[ecs.TagEtag] = etag
+	}
+	if lockID != "" {
+		tags[tagLock] = lockID
+	}
+	return b.driver.RunWithTags(ctx, env, tags, cmd...)
 }
 
 func (b *ByocAws) Delete(ctx context.Context, req *defangv1.DeleteRequest) (*defangv1.DeleteResponse, error) {
@@ -386,7 +562,7 @@ func (b *ByocAws) Delete(ctx context.Context, req *defangv1.DeleteRequest) (*def
 		return nil, err
 	}
 	// FIXME: this should only delete the services that are specified in the request, not all
-	taskArn, err := b.runCdCommand(ctx, "up", "")
+	taskArn, err := b.runCdCommand(ctx, "", "up", "")
 	if err != nil {
 		return nil, annotateAwsError(err)
 	}
@@ -419,27 +595,80 @@ func (b *ByocAws) getClusterNames() []string {
 }
 
 func (b ByocAws) GetServices(ctx context.Context) (*defangv1.ListServicesResponse, error) {
+	bucketName, s3Client, err := b.projectBucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := b.projectPath()
+	term.Debug(" - Getting services from", bucketName, path)
+	project, err := getProjectVersion(ctx, s3Client, bucketName, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.checkProjectRegion(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// checkProjectRegion returns an error if the services were deployed in another region, unless IgnoreRegionMismatch is set
+func (b ByocAws) checkProjectRegion(project *defangv1.ListServicesResponse) error {
+	// Older deployments didn't record the region
+	if project.Region == "" || project.Region == b.serviceRegion().String() {
+		return nil
+	}
+	err := fmt.Errorf("stack %q of project %q is deployed in region %s; use --region %s", b.pulumiStack, b.pulumiProject, project.Region, project.Region)
+	if IgnoreRegionMismatch {
+		term.Warn(" !", err)
+		return nil
+	}
+	return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w, or --ignore-region-mismatch to continue anyway", err))
+}
+
+// serviceRegion returns the region of the services, which defaults to the CD region
+func (b ByocAws) serviceRegion() aws.Region {
+	if b.region != "" {
+		return b.region
+	}
+	return b.driver.Region
+}
+
+// serviceDriver returns a copy of the CD driver for the region of the services
+func (b ByocAws) serviceDriver() *ecs.AwsEcs {
+	driver := b.driver.AwsEcs
+	driver.Region = b.serviceRegion()
+	return &driver
+}
+
+// projectBucket returns the name of the bucket with the project state and a client for it
+func (b ByocAws) projectBucket(ctx context.Context) (string, *s3.Client, error) {
 	bucketName := b.bucketName()
 	if bucketName == "" {
 		if err := b.driver.FillOutputs(ctx); err != nil {
-			return nil, annotateAwsError(err)
+			return "", nil, annotateAwsError(err)
 		}
 		bucketName = b.bucketName()
 	}
 
 	cfg, err := b.driver.LoadConfig(ctx)
 	if err != nil {
-		return nil, annotateAwsError(err)
+		return "", nil, annotateAwsError(err)
 	}
+	return bucketName, s3.NewFromConfig(cfg), nil
+}
 
-	s3Client := s3.NewFromConfig(cfg)
-	// Path to the state file, Defined at: https://github.com/defang-io/defang-mvp/blob/main/pulumi/cd/byoc/aws/index.ts#L89
-	path := fmt.Sprintf("projects/%s/%s/project.pb", b.pulumiProject, b.pulumiStack)
+// projectPath returns the path to the state file, defined at: https://github.com/defang-io/defang-mvp/blob/main/pulumi/cd/byoc/aws/index.ts#L89
+func (b ByocAws) projectPath() string {
+	return fmt.Sprintf("projects/%s/%s/project.pb", b.pulumiProject, b.pulumiStack)
+}
 
-	term.Debug(" - Getting services from", bucketName, path)
+// getProjectVersion reads the given version of the state file, or the latest if versionID is nil
+func getProjectVersion(ctx context.Context, s3Client *s3.Client, bucketName, path string, versionID *string) (*defangv1.ListServicesResponse, error) {
 	getObjectOutput, err := s3Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: &bucketName,
-		Key:    &path,
+		Bucket:    &bucketName,
+		Key:       &path,
+		VersionId: versionID,
 	})
 	if err != nil {
 		return nil, annotateAwsError(err)
@@ -469,14 +698,14 @@ func (b ByocAws) PutConfig(ctx context.Context, secret *defangv1.SecretValue) er
 	}
 	fqn := b.getSecretID(secret.Name)
 	term.Debug(" - Putting parameter", fqn)
-	err := b.driver.PutSecret(ctx, fqn, secret.Value)
+	err := b.serviceDriver().PutSecret(ctx, fqn, secret.Value)
 	return annotateAwsError(err)
 }
 
 func (b ByocAws) ListConfig(ctx context.Context) (*defangv1.Secrets, error) {
 	prefix := b.getSecretID("")
 	term.Debug(" - Listing parameters with prefix", prefix)
-	awsSecrets, err := b.driver.ListSecretsByPrefix(ctx, prefix)
+	awsSecrets, err := b.serviceDriver().ListSecretsByPrefix(ctx, prefix)
 	if err != nil {
 		return nil, err
 	}
@@ -509,14 +738,11 @@ func (b *ByocAws) Tail(ctx context.Context, req *defangv1.TailRequest) (client.S
 	ctx, cancel := context.WithCancelCause(ctx)
 
 	etag := req.Etag
-	// if etag == "" && req.Service == "cd" {
-	// 	etag = awsecs.GetTaskID(b.cdTaskArn); TODO: find the last CD task
-	// }
 	// How to tail multiple tasks/services at once?
 	//  * No Etag, no service:	tail all tasks/services
 	//  * Etag, no service: 	tail all tasks/services with that Etag
-	//  * No Etag, service:		tail all tasks/services with that service name
-	//  * Etag, service:		tail that task/service
+	//  * No Etag, service(s):	tail all tasks/services with those service names
+	//  * Etag, service(s):		tail those tasks/services
 	var err error
 	var taskArn ecs.TaskArn
 	var eventStream ecs.EventStream
@@ -527,10 +753,16 @@ func (b *ByocAws) Tail(ctx context.Context, req *defangv1.TailRequest) (client.S
 		etag = "" // no need to filter by etag
 	} else {
 		// Tail CD, kaniko, and all services
-		kanikoTail := ecs.LogGroupInput{LogGroupARN: b.driver.MakeARN("logs", "log-group:"+b.stackDir("builds"))} // must match logic in ecs/common.ts
-		servicesTail := ecs.LogGroupInput{LogGroupARN: b.driver.MakeARN("logs", "log-group:"+b.stackDir("logs"))} // must match logic in ecs/common.ts
+		services := b.serviceDriver()
+		kanikoTail := ecs.LogGroupInput{LogGroupARN: services.MakeARN("logs", "log-group:"+b.stackDir("builds"))} // must match logic in ecs/common.ts
+		servicesTail := ecs.LogGroupInput{LogGroupARN: services.MakeARN("logs", "log-group:"+b.stackDir("logs"))} // must match logic in ecs/common.ts
 		cdTail := ecs.LogGroupInput{LogGroupARN: b.driver.LogGroupARN}
-		taskArn = b.cdTasks[etag]
+		if etag != "" {
+			// Find the CD task by its tag, in case the deployment was started from another shell
+			if taskArn, err = b.findCdTask(ctx, etag); err != nil {
+				term.Debug(" - Failed to find the CD task:", err)
+			}
+		}
 		if taskArn != nil {
 			// Only tail the logstreams for the CD task
 			cdTail.LogStreamNames = []string{ecs.GetLogStreamForTaskID(ecs.GetTaskID(taskArn))}
@@ -550,15 +782,16 @@ func (b *ByocAws) Tail(ctx context.Context, req *defangv1.TailRequest) (client.S
 		}()
 	}
 
-	return newByocServerStream(ctx, eventStream, etag, req.Service), nil
+	services := req.Services
+	if len(services) == 0 && req.Service != "" {
+		services = []string{req.Service}
+	}
+	return newByocServerStream(ctx, eventStream, etag, services), nil
 }
 
 // This function was copied from Fabric controller and slightly modified to work with BYOC
 func (b ByocAws) update(ctx context.Context, service *defangv1.Service) (*defangv1.ServiceInfo, error) {
-	if err := b.quota.Validate(service); err != nil {
-		return nil, err
-	}
-
+	// The quotas were already validated for all services at once
 	// Check to make sure all required secrets are present in the secrets store
 	missing, err := b.checkForMissingSecrets(ctx, service.Secrets)
 	if err != nil {
@@ -630,7 +863,7 @@ func (b ByocAws) checkForMissingSecrets(ctx context.Context, secrets []*defangv1
 		return nil, nil // no secrets to check
 	}
 	prefix := b.getSecretID("")
-	sorted, err := b.driver.ListSecretsByPrefix(ctx, prefix)
+	sorted, err := b.serviceDriver().ListSecretsByPrefix(ctx, prefix)
 	if err != nil {
 		return nil, err
 	}
@@ -684,6 +917,10 @@ func (b ByocAws) getPrivateFqdn(fqn qualifiedName) string {
 
 func (b ByocAws) getProjectDomain(zone string) string {
 	projectLabel := dnsSafeLabel(b.pulumiProject)
+	if b.pulumiStack != DefaultStack {
+		// Other stacks get their own label, so they can't collide with a project named like "project-stack" or "stack"
+		return projectLabel + "." + b.pulumiStack + "." + dnsSafe(zone)
+	}
 	if projectLabel == dnsSafeLabel(b.tenantID) {
 		return dnsSafe(zone) // the zone will already have the tenant ID
 	}
@@ -707,7 +944,20 @@ func (b *ByocAws) BootstrapCommand(ctx context.Context, command string) (string,
 	if err := b.setUp(ctx); err != nil {
 		return "", err
 	}
-	cdTaskArn, err := b.runCdCommand(ctx, command)
+	var cdTaskArn ecs.TaskArn
+	var err error
+	if command == "change-secrets-provider" && b.secretsProvider() == SecretsProviderPassphrase {
+		return "", errors.New("stack secrets can only be migrated to the KMS secrets provider")
+	}
+	if command == "cancel" {
+		// Cancel must not wait for the lock: stop the CD task that holds it and let Pulumi clean up
+		if err := b.unlockCd(ctx, true); err != nil {
+			return "", err
+		}
+		cdTaskArn, err = b.startCdTask(ctx, "", "", command)
+	} else {
+		cdTaskArn, err = b.runCdCommand(ctx, "", command)
+	}
 	if err != nil || cdTaskArn == nil {
 		return "", annotateAwsError(err)
 	}
@@ -715,7 +965,18 @@ func (b *ByocAws) BootstrapCommand(ctx context.Context, command string) (string,
 }
 
 func (b *ByocAws) Destroy(ctx context.Context) (string, error) {
-	return b.BootstrapCommand(ctx, "down")
+	if err := b.tearDownBastions(ctx); err != nil {
+		term.Warn("Failed to delete the bastion stacks:", annotateAwsError(err))
+	}
+	etag, err := b.BootstrapCommand(ctx, "down")
+	if err != nil {
+		return "", err
+	}
+	// The running tasks already pulled their images, so the registry credentials are no longer needed
+	if err := b.tearDownRegistrySecrets(ctx); err != nil {
+		term.Warn("Failed to delete the registry secrets:", annotateAwsError(err))
+	}
+	return etag, nil
 }
 
 func (b *ByocAws) DeleteConfig(ctx context.Context, secrets *defangv1.Secrets) error {
@@ -724,7 +985,7 @@ func (b *ByocAws) DeleteConfig(ctx context.Context, secrets *defangv1.Secrets) e
 		ids[i] = b.getSecretID(name)
 	}
 	term.Debug(" - Deleting parameters", ids)
-	if err := b.driver.DeleteSecrets(ctx, ids...); err != nil {
+	if err := b.serviceDriver().DeleteSecrets(ctx, ids...); err != nil {
 		return annotateAwsError(err)
 	}
 	return nil
@@ -734,6 +995,40 @@ func (b *ByocAws) Restart(ctx context.Context, names ...string) (types.ETag, err
 	return "", errors.New("not yet implemented for BYOC; please use the AWS ECS dashboard") // FIXME: implement this for BYOC
 }
 
+func (b *ByocAws) Exec(ctx context.Context, service string, args ...string) error {
+	if err := b.setUp(ctx); err != nil {
+		return err
+	}
+
+	taskArn, err := b.findServiceTask(ctx, service)
+	if err != nil {
+		return err
+	}
+	term.Debug(" - Executing", args, "in task", *taskArn)
+	isService := func(name string) bool { return isServiceResource(name, service) }
+	return annotateAwsError(ecs.ExecuteCommand(ctx, taskArn, isService, args...))
+}
+
+// findServiceTask returns a running task of the given service in any of the clusters
+func (b *ByocAws) findServiceTask(ctx context.Context, service string) (ecs.TaskArn, error) {
+	isService := func(name string) bool { return isServiceResource(name, service) }
+	services := b.serviceDriver()
+	for _, cluster := range b.getClusterNames() {
+		taskArn, err := services.FindServiceTask(ctx, cluster, isService)
+		if err != nil {
+			var clusterNotFound *ecsTypes.ClusterNotFoundException
+			if errors.As(err, &clusterNotFound) {
+				continue // the GPU cluster is only created when needed
+			}
+			return nil, annotateAwsError(err)
+		}
+		if taskArn != nil {
+			return taskArn, nil
+		}
+	}
+	return nil, fmt.Errorf("service %q not found", service)
+}
+
 func (b *ByocAws) BootstrapList(ctx context.Context) ([]string, error) {
 	bucketName := b.bucketName()
 	if bucketName == "" {
@@ -793,7 +1088,18 @@ func (b *ByocAws) ServiceDNS(name string) string {
 	return dnsSafeLabel(name) // TODO: consider merging this with getPrivateFqdn
 }
 
+// validateStack checks the stack name, which ends up in stack names and domain names
+func (b *ByocAws) validateStack() error {
+	if !validStackName.MatchString(b.pulumiStack) {
+		return fmt.Errorf("invalid stack name %q; must be lowercase alphanumeric or -, start with a letter, and be at most 20 characters", b.pulumiStack)
+	}
+	return nil
+}
+
 func (b *ByocAws) LoadProjectName() (string, error) {
+	if err := b.validateStack(); err != nil {
+		return "", err
+	}
 	if b.pulumiProject != "" {
 		return b.pulumiProject, nil
 	}
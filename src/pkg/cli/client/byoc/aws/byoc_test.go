@@ -1,10 +1,20 @@
 package aws
 
 import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	compose "github.com/compose-spec/compose-go/v2/types"
+	"github.com/defang-io/defang/src/pkg"
 	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/clouds/aws"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn/outputs"
 	"github.com/defang-io/defang/src/pkg/types"
 	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
 )
@@ -61,6 +71,140 @@ func TestDomainMultipleProjectSupport(t *testing.T) {
 	}
 }
 
+func TestDomainStackSupport(t *testing.T) {
+	tests := []struct {
+		ProjectName string
+		TenantID    types.TenantID
+		Stack       string
+		Domain      string
+	}{
+		{"project1", "tenant1", DefaultStack, "project1.example.com"},
+		{"project1", "tenant1", "staging", "project1.staging.example.com"},
+		{"tenant1", "tenant1", DefaultStack, "example.com"},
+		{"tenant1", "tenant1", "staging", "tenant1.staging.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ProjectName+","+tt.Stack, func(t *testing.T) {
+			b := NewByoc(tt.TenantID, &client.GrpcClient{Loader: FakeLoader{ProjectName: tt.ProjectName}})
+			b.pulumiStack = tt.Stack
+			if _, err := b.LoadProject(); err != nil {
+				t.Fatalf("LoadCompose() failed: %v", err)
+			}
+			if domain := b.getProjectDomain("example.com"); domain != tt.Domain {
+				t.Errorf("expected domain %q, got %q", tt.Domain, domain)
+			}
+		})
+	}
+}
+
+func TestDomainStackCollision(t *testing.T) {
+	projects := []struct {
+		ProjectName string
+		Stack       string
+	}{
+		{"project1", DefaultStack},
+		{"project1", "staging"},
+		{"project1-staging", DefaultStack},
+		{"staging", DefaultStack},
+		{"tenant1", DefaultStack},
+		{"tenant1", "staging"},
+	}
+
+	domains := make(map[string]string)
+	for _, p := range projects {
+		b := NewByoc("tenant1", &client.GrpcClient{Loader: FakeLoader{ProjectName: p.ProjectName}})
+		b.pulumiStack = p.Stack
+		if _, err := b.LoadProject(); err != nil {
+			t.Fatalf("LoadCompose() failed: %v", err)
+		}
+		domain := b.getProjectDomain("example.com")
+		if other, ok := domains[domain]; ok {
+			t.Errorf("domain %q of %s/%s collides with %s", domain, p.ProjectName, p.Stack, other)
+		}
+		domains[domain] = p.ProjectName + "/" + p.Stack
+	}
+}
+
+func TestInvalidStackName(t *testing.T) {
+	for _, stack := range []string{"", "Staging", "1st", "prod.eu", "a-very-long-stack-name-indeed"} {
+		b := NewByoc("tenant1", &client.GrpcClient{Loader: FakeLoader{ProjectName: "project1"}})
+		b.pulumiStack = stack
+		if _, err := b.LoadProject(); err == nil {
+			t.Errorf("expected error for stack %q", stack)
+		}
+	}
+}
+
+func TestInvalidStackNameWithProjectName(t *testing.T) {
+	b := NewByoc("tenant1", &client.GrpcClient{Loader: FakeLoader{ProjectName: "project1"}})
+	b.pulumiProject = "project1" // as if from COMPOSE_PROJECT_NAME
+	b.pulumiStack = "Staging"
+	if _, err := b.LoadProjectName(); err == nil {
+		t.Error("expected error for stack \"Staging\"")
+	}
+}
+
+func TestEnvironmentRegions(t *testing.T) {
+	tests := []struct {
+		CdRegion      aws.Region
+		Region        aws.Region
+		ServiceRegion string
+	}{
+		{"us-west-2", "", "us-west-2"},
+		{"us-west-2", "eu-central-1", "eu-central-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.CdRegion)+","+string(tt.Region), func(t *testing.T) {
+			b := NewByoc("tenant1", &client.GrpcClient{Loader: FakeLoader{ProjectName: "project1"}})
+			b.driver.Region = tt.CdRegion
+			b.driver.BucketName = "bucket"
+			b.driver.TaskDefARN = "arn:aws:ecs:" + string(tt.CdRegion) + ":123456789012:task-definition/defang-cd:1"
+			b.region = tt.Region
+			if _, err := b.LoadProject(); err != nil {
+				t.Fatalf("LoadCompose() failed: %v", err)
+			}
+
+			env := b.environment()
+			if env["AWS_REGION"] != tt.ServiceRegion {
+				t.Errorf("expected AWS_REGION %q, got %q", tt.ServiceRegion, env["AWS_REGION"])
+			}
+			if backend := "s3://bucket?region=" + string(tt.CdRegion) + "&awssdk=v2"; env["PULUMI_BACKEND_URL"] != backend {
+				t.Errorf("expected PULUMI_BACKEND_URL %q, got %q", backend, env["PULUMI_BACKEND_URL"])
+			}
+			if arn := b.serviceDriver().MakeARN("logs", "log-group:x"); !strings.HasPrefix(arn, "arn:aws:logs:"+tt.ServiceRegion+":") {
+				t.Errorf("expected ARN in region %q, got %q", tt.ServiceRegion, arn)
+			}
+		})
+	}
+}
+
+func TestSecretsProvider(t *testing.T) {
+	defer func(provider string) { SecretsProvider = provider }(SecretsProvider)
+
+	tests := []struct {
+		SecretsProvider string
+		SecretsKeyARN   string
+		Want            string
+	}{
+		{SecretsProviderKms, "arn:aws:kms:us-west-2:123456789012:key/abc", "awskms:///arn:aws:kms:us-west-2:123456789012:key/abc"},
+		{SecretsProviderKms, "", SecretsProviderPassphrase}, // CD stack without the key
+		{SecretsProviderPassphrase, "arn:aws:kms:us-west-2:123456789012:key/abc", SecretsProviderPassphrase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.SecretsProvider+","+tt.SecretsKeyARN, func(t *testing.T) {
+			SecretsProvider = tt.SecretsProvider
+			b := NewByoc("tenant1", nil)
+			b.driver.SecretsKeyARN = tt.SecretsKeyARN
+			if got := b.secretsProvider(); got != tt.Want {
+				t.Errorf("expected %q, got %q", tt.Want, got)
+			}
+		})
+	}
+}
+
 type FakeLoader struct {
 	ProjectName string
 }
@@ -76,3 +220,190 @@ func (f FakeLoader) LoadWithDefaultProjectName(defaultName string) (*compose.Pro
 func (f FakeLoader) LoadWithProjectName(projectName string) (*compose.Project, error) {
 	return &compose.Project{Name: projectName}, nil
 }
+
+func TestMatchServiceName(t *testing.T) {
+	services := map[string]*defangv1.ServiceInfo{"app": nil, "app-worker": nil, "db": nil}
+	tests := []struct {
+		ecsService string
+		want       string
+	}{
+		{"app", "app"},
+		{"app-1234567", "app"},
+		{"app-worker-1234567", "app-worker"},
+		{"db_abcdef123456", "db"},
+		{"dbx-1234567", ""},
+		{"redis-1234567", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ecsService, func(t *testing.T) {
+			if got := matchServiceName(tt.ecsService, services); got != tt.want {
+				t.Errorf("matchServiceName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCdTaskPolicy(t *testing.T) {
+	policy, err := json.Marshal(CdTaskPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Statement []struct {
+			Sid       string
+			Action    pkg.OneOrList
+			Resource  pkg.OneOrList
+			Condition map[string]any
+		}
+	}
+	if err := json.Unmarshal(policy, &doc); err != nil {
+		t.Fatal(err)
+	}
+	isReadOnly := func(action string) bool {
+		_, name, _ := strings.Cut(action, ":")
+		return strings.HasPrefix(name, "Describe") || strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+	}
+	for _, stmt := range doc.Statement {
+		allResources := slices.Contains(stmt.Resource, "*")
+		for _, action := range stmt.Action {
+			if action == "*" || strings.HasSuffix(action, ":*") {
+				t.Errorf("%s: expected no wildcard action, got %q", stmt.Sid, action)
+			}
+			if !allResources || stmt.Condition != nil {
+				continue
+			}
+			switch {
+			case action == "iam:PassRole", action == "sts:AssumeRole":
+				t.Errorf("%s: %s should not be allowed on all resources", stmt.Sid, action)
+			case !isReadOnly(action) && !slices.Contains(unscopedActions, action):
+				t.Errorf("%s: %s should be scoped to Defang resources", stmt.Sid, action)
+			}
+		}
+	}
+}
+
+func TestIsServiceResource(t *testing.T) {
+	tests := []struct {
+		resourceName string
+		want         bool
+	}{
+		{"app", true},
+		{"app-1234567", true},
+		{"app_abcdef123456", true},
+		{"app-worker", false},
+		{"app-worker-1234567", false},
+		{"app_worker", false},
+		{"apps-1234567", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceName, func(t *testing.T) {
+			if got := isServiceResource(tt.resourceName, "app"); got != tt.want {
+				t.Errorf("isServiceResource(%q, \"app\") = %v, want %v", tt.resourceName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsVersionNotFound(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&s3types.NoSuchKey{}, true},
+		{annotateAwsError(&smithy.GenericAPIError{Code: "NoSuchVersion"}), true},
+		{&smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{errors.New("connection reset"), false},
+	}
+	for _, tt := range tests {
+		if got := isVersionNotFound(tt.err); got != tt.want {
+			t.Errorf("isVersionNotFound(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCheckProjectRegion(t *testing.T) {
+	b := NewByoc("tenant1", &client.GrpcClient{Loader: FakeLoader{ProjectName: "project1"}})
+	b.region = "us-west-2"
+
+	if err := b.checkProjectRegion(&defangv1.ListServicesResponse{}); err != nil {
+		t.Errorf("expected no error for a project without region, got %v", err)
+	}
+	if err := b.checkProjectRegion(&defangv1.ListServicesResponse{Region: "us-west-2"}); err != nil {
+		t.Errorf("expected no error for the same region, got %v", err)
+	}
+	if err := b.checkProjectRegion(&defangv1.ListServicesResponse{Region: "eu-west-1"}); err == nil {
+		t.Error("expected an error for another region")
+	}
+
+	IgnoreRegionMismatch = true
+	defer func() { IgnoreRegionMismatch = false }()
+	if err := b.checkProjectRegion(&defangv1.ListServicesResponse{Region: "eu-west-1"}); err != nil {
+		t.Errorf("expected only a warning with IgnoreRegionMismatch, got %v", err)
+	}
+}
+
+func TestCdSpot(t *testing.T) {
+	tests := []struct {
+		setting, stackSetting string
+		want                  bool
+		wantErr               bool
+	}{
+		{"", "", true, false},
+		{"", "false", false, false},
+		{"true", "false", true, false},
+		{"0", "true", false, false},
+		{"FALSE", "", false, false},
+		{"no", "", false, true},
+	}
+	for _, tt := range tests {
+		got, err := cdSpot(tt.setting, tt.stackSetting)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("cdSpot(%q, %q) error = %v, want error %v", tt.setting, tt.stackSetting, err, tt.wantErr)
+		} else if got != tt.want {
+			t.Errorf("cdSpot(%q, %q) = %v, want %v", tt.setting, tt.stackSetting, got, tt.want)
+		}
+	}
+}
+
+func TestKeepCdStackSettings(t *testing.T) {
+	var overrides cfn.TemplateOverrides
+	keepCdStackSettings(&overrides, nil)
+	if overrides.CreateSecretsKey {
+		t.Error("expected no secrets key for a new stack")
+	}
+
+	if len(overrides.EgressCIDRs) != 0 {
+		t.Errorf("expected no egress CIDRs for a new stack, got %v", overrides.EgressCIDRs)
+	}
+
+	stackOutputs := map[string]string{
+		outputs.EgressCIDRs:         "10.0.0.0/8,192.168.0.0/16",
+		outputs.LogRetentionDays:    "30",
+		outputs.PermissionsBoundary: "arn:aws:iam::123456789012:policy/boundary",
+		outputs.SecretsKeyARN:       "arn:aws:kms:us-west-2:123456789012:key/abc",
+		outputs.TaskRoleARN:         "arn:aws:iam::123456789012:role/cd",
+	}
+	keepCdStackSettings(&overrides, stackOutputs)
+	if !overrides.CreateSecretsKey {
+		t.Error("expected the existing secrets key to be kept")
+	}
+	if overrides.TaskRoleARN != "arn:aws:iam::123456789012:role/cd" {
+		t.Errorf("expected the existing task role, got %q", overrides.TaskRoleARN)
+	}
+	if overrides.PermissionsBoundaryARN != "arn:aws:iam::123456789012:policy/boundary" {
+		t.Errorf("expected the existing permissions boundary, got %q", overrides.PermissionsBoundaryARN)
+	}
+	if overrides.LogRetentionDays != 30 {
+		t.Errorf("expected the existing log retention, got %d", overrides.LogRetentionDays)
+	}
+	if !slices.Equal(overrides.EgressCIDRs, []string{"10.0.0.0/8", "192.168.0.0/16"}) {
+		t.Errorf("expected the existing egress CIDRs, got %v", overrides.EgressCIDRs)
+	}
+
+	overrides = cfn.TemplateOverrides{TaskRoleARN: "arn:aws:iam::123456789012:role/other"}
+	keepCdStackSettings(&overrides, stackOutputs)
+	if overrides.TaskRoleARN != "arn:aws:iam::123456789012:role/other" {
+		t.Errorf("expected the given task role to win, got %q", overrides.TaskRoleARN)
+	}
+}
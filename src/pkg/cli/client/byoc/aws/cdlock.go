@@ -0,0 +1,267 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/defang-io/defang/src/pkg"
+	"github.com/defang-io/defang/src/pkg/clouds/aws"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/term"
+)
+
+const (
+	cdLockGracePeriod  = time.Minute // time for the CD task to start after the lock was acquired
+	cdLockPollInterval = 5 * time.Second
+)
+
+// WaitForLock makes CD commands wait for the CD lock instead of failing when another CD task is running
+var WaitForLock = pkg.GetenvBool("DEFANG_WAIT_FOR_LOCK")
+
+// cdLock is the content of the lock file in the bucket; the lock is held as long as the CD task with the lock ID is running.
+type cdLock struct {
+	ID        string    `json:"id"` // also the Lock tag of the CD task
+	Command   string    `json:"command"`
+	Etag      string    `json:"etag,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	StartedBy string    `json:"startedBy"`
+}
+
+type ErrCdLocked struct {
+	Command   string
+	StartedAt time.Time
+	StartedBy string
+}
+
+func (e ErrCdLocked) Error() string {
+	return fmt.Sprintf("another CD task (%s) was started by %s at %s; use --wait-for-lock to wait for it, or 'defang cd unlock' if it's stuck",
+		e.Command, e.StartedBy, e.StartedAt.Local().Format(time.DateTime))
+}
+
+// lockPath returns the path to the lock file, next to the state file of the project
+func (b ByocAws) lockPath() string {
+	return fmt.Sprintf("projects/%s/%s/cd.lock", b.pulumiProject, b.pulumiStack)
+}
+
+// acquireCdLock acquires the CD lock for the project and returns the version ID of the lock file, so it can be released on failure.
+// S3 has no compare-and-swap, so every contender writes a new version of the lock file in the versioned bucket and the oldest version wins.
+func (b *ByocAws) acquireCdLock(ctx context.Context, lock cdLock) (string, error) {
+	waiting := false
+	for {
+		versionID, err := b.tryAcquireCdLock(ctx, lock)
+		var locked ErrCdLocked
+		if !WaitForLock || !errors.As(err, &locked) {
+			return versionID, err
+		}
+
+		if !waiting {
+			term.Infof(" * Waiting for the CD task (%s) started by %s at %s to finish", locked.Command, locked.StartedBy, locked.StartedAt.Local().Format(time.DateTime))
+			waiting = true
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(cdLockPollInterval):
+		}
+	}
+}
+
+func (b *ByocAws) tryAcquireCdLock(ctx context.Context, lock cdLock) (string, error) {
+	bucketName, s3Client, err := b.projectBucket(ctx)
+	if err != nil {
+		return "", err
+	}
+	path := b.lockPath()
+
+	body, err := json.Marshal(lock)
+	if err != nil {
+		return "", err
+	}
+	poo, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      &bucketName,
+		Key:         &path,
+		Body:        bytes.NewReader(body),
+		ContentType: ptr.String("application/json"),
+	})
+	if err != nil {
+		return "", annotateAwsError(err)
+	}
+	if poo.VersionId == nil {
+		return "", errors.New("the CD bucket must have versioning enabled")
+	}
+	versionID := *poo.VersionId
+
+	for {
+		versionIDs, err := listLockVersions(ctx, s3Client, bucketName, path)
+		if err != nil {
+			return "", err
+		}
+		if len(versionIDs) == 0 {
+			return "", errors.New("the CD lock was removed while acquiring it")
+		}
+		oldest := versionIDs[len(versionIDs)-1]
+		if oldest == versionID {
+			term.Debug(" - Acquired CD lock", lock.ID)
+			return versionID, nil
+		}
+
+		holder, err := getLockVersion(ctx, s3Client, bucketName, path, oldest)
+		if err != nil {
+			if isVersionNotFound(err) {
+				continue // the lock was released in the meantime; check again
+			}
+			// Don't break a lock we can't read: it might still be held
+			deleteLockVersion(ctx, s3Client, bucketName, path, versionID)
+			return "", fmt.Errorf("failed to read the CD lock; use 'defang cd unlock' if it's stuck: %w", err)
+		}
+		if b.isCdLockHeld(ctx, holder) {
+			deleteLockVersion(ctx, s3Client, bucketName, path, versionID)
+			return "", ErrCdLocked{Command: holder.Command, StartedAt: holder.StartedAt, StartedBy: holder.StartedBy}
+		}
+
+		// The lock is stale, because the CD task finished or never started; remove it and check again
+		term.Debug(" - Removing stale CD lock", holder.ID)
+		deleteLockVersion(ctx, s3Client, bucketName, path, oldest)
+	}
+}
+
+// isCdLockHeld returns true if the CD task with the lock ID is running or might still be starting
+func (b *ByocAws) isCdLockHeld(ctx context.Context, lock cdLock) bool {
+	taskInfos, err := b.driver.ListTasks(ctx)
+	if err != nil {
+		term.Debug(" - Failed to list CD tasks:", err)
+		return true // assume the worst
+	}
+	for _, ti := range taskInfos {
+		if ti.Tags[tagLock] == lock.ID {
+			return ti.StoppedAt.IsZero() && ti.Status != "STOPPED"
+		}
+	}
+	// The task is gone, or hasn't been started yet
+	return time.Since(lock.StartedAt) < cdLockGracePeriod
+}
+
+// releaseCdLock removes the given version of the lock file, if the CD task could not be started
+func (b *ByocAws) releaseCdLock(ctx context.Context, versionID string) {
+	bucketName, s3Client, err := b.projectBucket(ctx)
+	if err != nil {
+		term.Debug(" - Failed to release CD lock:", err)
+		return
+	}
+	deleteLockVersion(ctx, s3Client, bucketName, b.lockPath(), versionID)
+}
+
+// BootstrapUnlock forcibly removes the CD lock, for when a CD task is stuck
+func (b *ByocAws) BootstrapUnlock(ctx context.Context) error {
+	if err := b.setUp(ctx); err != nil {
+		return err
+	}
+	return b.unlockCd(ctx, false)
+}
+
+// unlockCd removes all versions of the lock file; if stop is true, it also stops the CD task that holds the lock
+func (b *ByocAws) unlockCd(ctx context.Context, stop bool) error {
+	bucketName, s3Client, err := b.projectBucket(ctx)
+	if err != nil {
+		return err
+	}
+	path := b.lockPath()
+
+	versionIDs, err := listLockVersions(ctx, s3Client, bucketName, path)
+	if err != nil {
+		return err
+	}
+	if stop && len(versionIDs) > 0 {
+		if holder, err := getLockVersion(ctx, s3Client, bucketName, path, versionIDs[len(versionIDs)-1]); err == nil {
+			b.stopCdTask(ctx, holder.ID)
+		}
+	}
+	for _, versionID := range versionIDs {
+		if _, err := s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket:    &bucketName,
+			Key:       &path,
+			VersionId: &versionID,
+		}); err != nil {
+			return annotateAwsError(err)
+		}
+	}
+	term.Debug(" - Removed", len(versionIDs), "versions of the CD lock")
+	return nil
+}
+
+// stopCdTask stops the running CD task with the given lock ID, if any
+func (b *ByocAws) stopCdTask(ctx context.Context, lockID string) {
+	taskInfos, err := b.driver.ListTasks(ctx)
+	if err != nil {
+		term.Debug(" - Failed to list CD tasks:", err)
+		return
+	}
+	for _, ti := range taskInfos {
+		if ti.Tags[tagLock] == lockID && ti.StoppedAt.IsZero() {
+			term.Info(" * Stopping CD task", ecs.GetTaskID(ti.TaskArn), "started by", ti.Tags[ecs.TagStartedBy])
+			if err := b.driver.Stop(ctx, ti.TaskArn); err != nil {
+				term.Warn(" ! Failed to stop CD task:", err)
+			}
+		}
+	}
+}
+
+// listLockVersions returns the version IDs of the lock file, newest first; delete markers are ignored
+func listLockVersions(ctx context.Context, s3Client *s3.Client, bucketName, path string) ([]string, error) {
+	lovo, err := s3Client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+		Bucket: &bucketName,
+		Prefix: &path,
+	})
+	if err != nil {
+		return nil, annotateAwsError(err)
+	}
+	var versionIDs []string
+	for _, version := range lovo.Versions {
+		if version.Key != nil && *version.Key == path && version.VersionId != nil {
+			versionIDs = append(versionIDs, *version.VersionId)
+		}
+	}
+	return versionIDs, nil
+}
+
+func getLockVersion(ctx context.Context, s3Client *s3.Client, bucketName, path, versionID string) (cdLock, error) {
+	var lock cdLock
+	goo, err := s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:    &bucketName,
+		Key:       &path,
+		VersionId: &versionID,
+	})
+	if err != nil {
+		return lock, annotateAwsError(err)
+	}
+	defer goo.Body.Close()
+	body, err := io.ReadAll(goo.Body)
+	if err != nil {
+		return lock, err
+	}
+	return lock, json.Unmarshal(body, &lock)
+}
+
+// isVersionNotFound returns true if the version of the object was deleted
+func isVersionNotFound(err error) bool {
+	var apiErr smithy.APIError
+	return aws.IsS3NoSuchKeyError(err) || (errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchVersion")
+}
+
+func deleteLockVersion(ctx context.Context, s3Client *s3.Client, bucketName, path, versionID string) {
+	if _, err := s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:    &bucketName,
+		Key:       &path,
+		VersionId: &versionID,
+	}); err != nil {
+		term.Debug(" - Failed to delete CD lock version", versionID+":", err)
+	}
+}
@@ -0,0 +1,321 @@
+package aws
+
+import "github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+
+// unscopedActions are the only actions that CdTaskPolicy allows on all resources without a condition, besides the
+// read-only Describe*, Get* and List* actions; AWS doesn't support resource-level permissions or tags for these.
+var unscopedActions = []string{
+	"ecr:GetAuthorizationToken",
+	"ecs:DeregisterTaskDefinition",
+	"route53:CreateHostedZone",
+	"sts:GetCallerIdentity",
+}
+
+// CdTaskPolicy returns the IAM policy of the CD task role: enough to deploy the services of a project, instead of AdministratorAccess.
+// Resources with names are scoped to the "Defang" or "defang" prefix of the resources that the CD task creates. Resources with
+// only an ID, like VPCs and load balancers, are scoped to the CreatedBy tag that the CD task adds when it creates them.
+// Roles for delegated zones in other accounts (x-defang-dns-role) must have a name with the "Defang-" prefix as well.
+func CdTaskPolicy() map[string]any {
+	createdByDefang := map[string]any{
+		"StringEquals": map[string]any{"aws:ResourceTag/CreatedBy": ecs.ProjectName},
+	}
+	taggedByDefang := map[string]any{
+		"StringEquals": map[string]any{"aws:RequestTag/CreatedBy": ecs.ProjectName},
+	}
+	return map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{
+			{
+				"Sid":    "AllowReadOnly",
+				"Effect": "Allow",
+				"Action": []string{
+					"acm:DescribeCertificate",
+					"acm:ListCertificates",
+					"acm:ListTagsForCertificate",
+					"application-autoscaling:DescribeScalableTargets",
+					"application-autoscaling:DescribeScalingPolicies",
+					"ec2:Describe*",
+					"ecr:DescribePullThroughCacheRules",
+					"ecr:DescribeRepositories",
+					"ecs:Describe*",
+					"ecs:List*",
+					"elasticloadbalancing:Describe*",
+					"logs:Describe*",
+					"route53:GetChange",
+					"route53:GetHostedZone",
+					"route53:ListHostedZones*",
+					"route53:ListResourceRecordSets",
+					"route53:ListTagsForResource",
+					"servicediscovery:Get*",
+					"servicediscovery:List*",
+				},
+				"Resource": "*",
+			},
+			{
+				"Sid":      "AllowUnscoped",
+				"Effect":   "Allow",
+				"Action":   unscopedActions,
+				"Resource": "*",
+			},
+			{
+				"Sid":    "AllowDefangEcs",
+				"Effect": "Allow",
+				"Action": []string{
+					"ecs:CreateService",
+					"ecs:DeleteCluster",
+					"ecs:DeleteService",
+					"ecs:PutClusterCapacityProviders",
+					"ecs:RunTask",
+					"ecs:StopTask",
+					"ecs:TagResource",
+					"ecs:UntagResource",
+					"ecs:UpdateCluster",
+					"ecs:UpdateClusterSettings",
+					"ecs:UpdateService",
+				},
+				"Resource": []string{
+					"arn:aws:ecs:*:*:cluster/" + DefangPrefix + "-*",
+					"arn:aws:ecs:*:*:service/" + DefangPrefix + "-*",
+					"arn:aws:ecs:*:*:task/" + DefangPrefix + "-*",
+					"arn:aws:ecs:*:*:task-definition/" + DefangPrefix + "-*",
+				},
+			},
+			{
+				"Sid":    "AllowCreateTaggedResources",
+				"Effect": "Allow",
+				"Action": []string{
+					"acm:RequestCertificate",
+					"application-autoscaling:RegisterScalableTarget",
+					"ec2:AllocateAddress",
+					"ec2:CreateInternetGateway",
+					"ec2:CreateLaunchTemplate",
+					"ec2:CreateNatGateway",
+					"ec2:CreateRouteTable",
+					"ec2:CreateSecurityGroup",
+					"ec2:CreateSubnet",
+					"ec2:CreateVpc",
+					"ec2:CreateVpcEndpoint",
+					"ec2:RunInstances",
+					"ecr:CreateRepository",
+					"ecs:CreateCluster",
+					"ecs:RegisterTaskDefinition",
+					"elasticloadbalancing:CreateListener",
+					"elasticloadbalancing:CreateLoadBalancer",
+					"elasticloadbalancing:CreateRule",
+					"elasticloadbalancing:CreateTargetGroup",
+					"servicediscovery:CreatePrivateDnsNamespace",
+					"servicediscovery:CreateService",
+				},
+				"Resource":  "*",
+				"Condition": taggedByDefang,
+			},
+			{
+				"Sid":       "AllowTagOnCreate",
+				"Effect":    "Allow",
+				"Action":    []string{"ec2:CreateTags", "elasticloadbalancing:AddTags"},
+				"Resource":  "*",
+				"Condition": taggedByDefang,
+			},
+			{
+				"Sid":    "AllowDefangTaggedResources",
+				"Effect": "Allow",
+				"Action": []string{
+					"acm:AddTagsToCertificate",
+					"acm:DeleteCertificate",
+					"application-autoscaling:DeleteScalingPolicy",
+					"application-autoscaling:DeregisterScalableTarget",
+					"application-autoscaling:PutScalingPolicy",
+					"ec2:AssociateAddress",
+					"ec2:AssociateRouteTable",
+					"ec2:AttachInternetGateway",
+					"ec2:AuthorizeSecurityGroupEgress",
+					"ec2:AuthorizeSecurityGroupIngress",
+					"ec2:CreateRoute",
+					"ec2:CreateTags",
+					"ec2:DeleteInternetGateway",
+					"ec2:DeleteLaunchTemplate",
+					"ec2:DeleteNatGateway",
+					"ec2:DeleteRoute",
+					"ec2:DeleteRouteTable",
+					"ec2:DeleteSecurityGroup",
+					"ec2:DeleteSubnet",
+					"ec2:DeleteTags",
+					"ec2:DeleteVpc",
+					"ec2:DeleteVpcEndpoints",
+					"ec2:DetachInternetGateway",
+					"ec2:DisassociateAddress",
+					"ec2:DisassociateRouteTable",
+					"ec2:ModifySecurityGroupRules",
+					"ec2:ModifySubnetAttribute",
+					"ec2:ModifyVpcAttribute",
+					"ec2:ReleaseAddress",
+					"ec2:RevokeSecurityGroupEgress",
+					"ec2:RevokeSecurityGroupIngress",
+					"ecr:BatchCheckLayerAvailability",
+					"ecr:BatchGetImage",
+					"ecr:BatchImportUpstreamImage",
+					"ecr:CompleteLayerUpload",
+					"ecr:DeleteRepository",
+					"ecr:GetDownloadUrlForLayer",
+					"ecr:InitiateLayerUpload",
+					"ecr:PutImage",
+					"ecr:PutLifecyclePolicy",
+					"ecr:TagResource",
+					"ecr:UploadLayerPart",
+					"elasticloadbalancing:AddTags",
+					"elasticloadbalancing:DeleteListener",
+					"elasticloadbalancing:DeleteLoadBalancer",
+					"elasticloadbalancing:DeleteRule",
+					"elasticloadbalancing:DeleteTargetGroup",
+					"elasticloadbalancing:ModifyListener",
+					"elasticloadbalancing:ModifyLoadBalancerAttributes",
+					"elasticloadbalancing:ModifyRule",
+					"elasticloadbalancing:ModifyTargetGroup",
+					"elasticloadbalancing:ModifyTargetGroupAttributes",
+					"elasticloadbalancing:RemoveTags",
+					"elasticloadbalancing:SetSecurityGroups",
+					"servicediscovery:DeleteNamespace",
+					"servicediscovery:DeleteService",
+					"servicediscovery:DeregisterInstance",
+					"servicediscovery:RegisterInstance",
+					"servicediscovery:TagResource",
+					"servicediscovery:UpdateService",
+				},
+				"Resource":  "*",
+				"Condition": createdByDefang,
+			},
+			{
+				"Sid":    "AllowDefangZones",
+				"Effect": "Allow",
+				"Action": []string{
+					"route53:ChangeResourceRecordSets",
+					"route53:ChangeTagsForResource",
+					"route53:DeleteHostedZone",
+				},
+				"Resource": "arn:aws:route53:::hostedzone/*", // Route 53 doesn't support tag conditions
+			},
+			{
+				"Sid":    "AllowDefangLogs",
+				"Effect": "Allow",
+				"Action": []string{
+					"logs:CreateLogGroup",
+					"logs:CreateLogStream",
+					"logs:DeleteLogGroup",
+					"logs:DeleteRetentionPolicy",
+					"logs:DeleteSubscriptionFilter",
+					"logs:PutLogEvents",
+					"logs:PutRetentionPolicy",
+					"logs:PutSubscriptionFilter",
+					"logs:TagResource",
+					"logs:UntagResource",
+				},
+				"Resource": "arn:aws:logs:*:*:log-group:/" + DefangPrefix + "/*",
+			},
+			{
+				"Sid":    "AllowDefangRoles",
+				"Effect": "Allow",
+				"Action": []string{
+					"iam:AttachRolePolicy",
+					"iam:CreatePolicy",
+					"iam:CreatePolicyVersion",
+					"iam:CreateRole",
+					"iam:DeletePolicy",
+					"iam:DeletePolicyVersion",
+					"iam:DeleteRole",
+					"iam:DeleteRolePolicy",
+					"iam:DetachRolePolicy",
+					"iam:GetPolicy",
+					"iam:GetPolicyVersion",
+					"iam:GetRole",
+					"iam:GetRolePolicy",
+					"iam:ListAttachedRolePolicies",
+					"iam:ListPolicyVersions",
+					"iam:ListRolePolicies",
+					"iam:PassRole",
+					"iam:PutRolePolicy",
+					"iam:TagPolicy",
+					"iam:TagRole",
+					"iam:UntagRole",
+					"iam:UpdateAssumeRolePolicy",
+				},
+				"Resource": []string{
+					"arn:aws:iam::*:role/" + DefangPrefix + "-*",
+					"arn:aws:iam::*:policy/" + DefangPrefix + "-*",
+				},
+			},
+			{
+				"Sid":      "AllowServiceLinkedRoles",
+				"Effect":   "Allow",
+				"Action":   "iam:CreateServiceLinkedRole",
+				"Resource": "arn:aws:iam::*:role/aws-service-role/*",
+				"Condition": map[string]any{
+					"StringEquals": map[string]any{"iam:AWSServiceName": []string{
+						"ecs.amazonaws.com",
+						"ecs.application-autoscaling.amazonaws.com",
+						"elasticloadbalancing.amazonaws.com",
+						"servicediscovery.amazonaws.com",
+					}},
+				},
+			},
+			{
+				"Sid":    "AllowDefangBuckets",
+				"Effect": "Allow",
+				"Action": []string{
+					"s3:CreateBucket",
+					"s3:DeleteBucket",
+					"s3:DeleteBucketPolicy",
+					"s3:DeleteObject",
+					"s3:DeleteObjectVersion",
+					"s3:GetBucketLocation",
+					"s3:GetBucketVersioning",
+					"s3:GetObject",
+					"s3:GetObjectVersion",
+					"s3:ListBucket",
+					"s3:ListBucketVersions",
+					"s3:PutBucketPolicy",
+					"s3:PutBucketPublicAccessBlock",
+					"s3:PutBucketTagging",
+					"s3:PutBucketVersioning",
+					"s3:PutEncryptionConfiguration",
+					"s3:PutLifecycleConfiguration",
+					"s3:PutObject",
+				},
+				"Resource": []string{
+					"arn:aws:s3:::defang-*",
+					"arn:aws:s3:::defang-*/*",
+				},
+			},
+			{
+				"Sid":    "AllowDefangParameters",
+				"Effect": "Allow",
+				"Action": []string{
+					"ssm:DeleteParameter",
+					"ssm:DeleteParameters",
+					"ssm:GetParameter",
+					"ssm:GetParameters",
+					"ssm:GetParametersByPath",
+					"ssm:PutParameter",
+				},
+				"Resource": "arn:aws:ssm:*:*:parameter/" + DefangPrefix + "/*",
+			},
+			{
+				"Sid":    "AllowSecretsKey", // the KMS key of the CD stack, which is tagged like the other resources
+				"Effect": "Allow",
+				"Action": []string{
+					"kms:Decrypt",
+					"kms:DescribeKey",
+					"kms:Encrypt",
+					"kms:GenerateDataKey*",
+				},
+				"Resource":  "arn:aws:kms:*:*:key/*",
+				"Condition": createdByDefang,
+			},
+			{
+				"Sid":      "AllowDnsRoles", // for delegated zones in other accounts
+				"Effect":   "Allow",
+				"Action":   "sts:AssumeRole",
+				"Resource": "arn:aws:iam::*:role/" + DefangPrefix + "-*",
+			},
+		},
+	}
+}
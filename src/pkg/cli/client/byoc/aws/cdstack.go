@@ -0,0 +1,85 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn"
+	"github.com/defang-io/defang/src/pkg/registry"
+)
+
+// BootstrapPreview returns the changes that setting up the CD stack would make, like after upgrading the CLI
+func (b *ByocAws) BootstrapPreview(ctx context.Context) (*client.CdStackPlan, error) {
+	if err := b.prepareCdDriver(ctx); err != nil {
+		return nil, err
+	}
+	changeSet, err := b.driver.PreviewSetUp(ctx, cdContainers())
+	if err != nil {
+		return nil, annotateAwsError(err)
+	}
+	plan := &client.CdStackPlan{ID: changeSet.ID, Changes: make([]client.CdStackChange, 0, len(changeSet.Changes))}
+	for _, change := range changeSet.Changes {
+		plan.Changes = append(plan.Changes, client.CdStackChange{
+			Action:      change.Action,
+			Resource:    change.LogicalID,
+			Type:        change.ResourceType,
+			Replacement: change.Replacement,
+		})
+	}
+	return plan, nil
+}
+
+// BootstrapSetUp creates or updates the CD stack with the changes of the plan from BootstrapPreview
+func (b *ByocAws) BootstrapSetUp(ctx context.Context, plan *client.CdStackPlan) error {
+	return annotateAwsError(b.driver.ExecuteChangeSet(ctx, plan.ID))
+}
+
+// BootstrapDiscard discards the plan from BootstrapPreview without changing the CD stack
+func (b *ByocAws) BootstrapDiscard(ctx context.Context, plan *client.CdStackPlan) error {
+	return annotateAwsError(b.driver.DiscardChangeSet(ctx, plan.ID))
+}
+
+// BootstrapDrift returns the resources of the CD stack that were changed outside of CloudFormation
+func (b *ByocAws) BootstrapDrift(ctx context.Context) ([]client.CdStackDrift, error) {
+	stackDrifts, err := b.driver.DetectDrift(ctx)
+	if err != nil {
+		return nil, annotateAwsError(err)
+	}
+	drifts := make([]client.CdStackDrift, 0, len(stackDrifts))
+	for _, drift := range stackDrifts {
+		drifts = append(drifts, client.CdStackDrift{
+			Resource:   drift.LogicalID,
+			Type:       drift.ResourceType,
+			Status:     drift.Status,
+			Properties: drift.Properties,
+		})
+	}
+	return drifts, nil
+}
+
+// PutRegistryCredentials stores the credentials for private registries in Secrets Manager and returns the ARNs of the secrets by registry host
+func (b *ByocAws) PutRegistryCredentials(ctx context.Context, creds map[string]registry.Credentials) (map[string]string, error) {
+	// The secrets are in their own stack per project, in the region of the services; it's deleted by Destroy
+	driver := cfn.New(b.registryStackName(), b.serviceRegion())
+	driver.Overrides.RegistryCredentials = creds
+	arns, err := driver.SetUpRegistrySecrets(ctx)
+	if err != nil {
+		return nil, annotateAwsError(err)
+	}
+	return arns, nil
+}
+
+// tearDownRegistrySecrets deletes the stack with the secrets of PutRegistryCredentials, if any
+func (b *ByocAws) tearDownRegistrySecrets(ctx context.Context) error {
+	return cfn.New(b.registryStackName(), b.serviceRegion()).TearDown(ctx)
+}
+
+// registryStackName returns the name of the stack with the registry secrets of the project; stack names can't have underscores
+func (b *ByocAws) registryStackName() string {
+	if b.pulumiProject == "" {
+		panic("pulumiProject not set")
+	}
+	return fmt.Sprintf("%s-registry-%s-%s", CdTaskPrefix, strings.ReplaceAll(b.pulumiProject, "_", "-"), b.pulumiStack)
+}
@@ -0,0 +1,98 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
+)
+
+// BootstrapTasks returns the running and recently stopped CD tasks, newest first, using the tags set by runCdCommand
+func (b *ByocAws) BootstrapTasks(ctx context.Context) ([]client.CdTask, error) {
+	if err := b.setUp(ctx); err != nil {
+		return nil, err
+	}
+
+	taskInfos, err := b.driver.ListTasks(ctx)
+	if err != nil {
+		return nil, annotateAwsError(err)
+	}
+	tasks := make([]client.CdTask, 0, len(taskInfos))
+	for _, ti := range taskInfos {
+		tasks = append(tasks, newCdTask(ti))
+	}
+	return tasks, nil
+}
+
+// BootstrapQueryLogs returns all log events of the given CD task, oldest first, for as long as the CD logs are kept
+func (b *ByocAws) BootstrapQueryLogs(ctx context.Context, taskID string) ([]client.CdLogEvent, error) {
+	// Only read the outputs, so the CD stack is never changed while its logs are being exported
+	if err := b.driver.FillOutputs(ctx); err != nil {
+		return nil, annotateAwsError(err)
+	}
+
+	var streams []string
+	for _, container := range cdContainers() {
+		streams = append(streams, path.Join(ecs.AwsLogsStreamPrefix, container.Name, taskID)) // per "awslogs" driver
+	}
+	logEvents, err := ecs.Query(ctx, ecs.LogGroupInput{LogGroupARN: b.driver.LogGroupARN, LogStreamNames: streams}, time.Unix(0, 0), time.Now())
+	if err != nil {
+		return nil, annotateAwsError(err)
+	}
+	events := make([]client.CdLogEvent, 0, len(logEvents))
+	for _, e := range logEvents {
+		events = append(events, client.CdLogEvent{
+			Stream:    ptr.ToString(e.LogStreamName),
+			Timestamp: time.UnixMilli(ptr.ToInt64(e.Timestamp)),
+			Message:   ptr.ToString(e.Message),
+		})
+	}
+	return events, nil
+}
+
+func newCdTask(ti ecs.TaskInfo) client.CdTask {
+	task := client.CdTask{
+		ID:        ecs.GetTaskID(ti.TaskArn),
+		Command:   ti.Tags[ecs.TagCommand],
+		Etag:      ti.Tags[ecs.TagEtag],
+		Project:   ti.Tags[tagProject],
+		Status:    ti.Status,
+		Reason:    ti.StopReason,
+		StartedAt: ti.CreatedAt,
+		StartedBy: ti.Tags[ecs.TagStartedBy],
+		StoppedAt: ti.StoppedAt,
+	}
+	if startedAt, err := time.Parse(time.RFC3339, ti.Tags[ecs.TagStartedAt]); err == nil {
+		task.StartedAt = startedAt
+	}
+	if ti.ExitCode != nil {
+		task.Status = fmt.Sprintf("%s (exit code %d)", ti.Status, *ti.ExitCode)
+	}
+	return task
+}
+
+// findCdTask returns the CD task for the given deployment ID, or nil if there's no such task (anymore)
+func (b *ByocAws) findCdTask(ctx context.Context, etag types.ETag) (ecs.TaskArn, error) {
+	if taskArn, ok := b.cdTasks[etag]; ok {
+		return taskArn, nil
+	}
+
+	taskInfos, err := b.driver.ListTasks(ctx)
+	if err != nil {
+		return nil, annotateAwsError(err)
+	}
+	for _, ti := range taskInfos {
+		if ti.Tags[ecs.TagEtag] == etag {
+			term.Debug(" - Found CD task", *ti.TaskArn, "for deployment", etag)
+			b.cdTasks[etag] = ti.TaskArn
+			return ti.TaskArn, nil
+		}
+	}
+	return nil, nil // stopped tasks are only kept for about an hour
+}
@@ -1,15 +1,65 @@
 package aws
 
 import (
+	"os"
+	"regexp"
+
 	"github.com/defang-io/defang/src/pkg"
 )
 
 const (
-	CdTaskPrefix = "defang-cd" // WARNING: renaming this practically deletes the Pulumi state
-	DefangPrefix = "Defang"    // prefix for all resources created by Defang
+	CdTaskPrefix     = "defang-cd" // WARNING: renaming this practically deletes the Pulumi state
+	DefangPrefix     = "Defang"    // prefix for all resources created by Defang
+	DefaultStack     = "beta"      // WARNING: the stack of all existing deployments; changing this practically deletes them
+	legacyPassphrase = "asdf"      // the Pulumi passphrase of stacks that were created before the KMS secrets provider
+	tagLock          = "Lock"      // tag on the CD task with the ID of the CD lock it holds
+	tagProject       = "Project"   // tag on the CD task with the name of the project
+)
+
+const (
+	SecretsProviderKms        = "awskms"
+	SecretsProviderPassphrase = "passphrase"
 )
 
 var (
 	// Changing this will cause issues if two clients with different versions are using the same account
 	CdImage = pkg.Getenv("DEFANG_CD_IMAGE", "public.ecr.aws/defang-io/cd:public-beta")
+	// CdEgressCIDRs restricts the outbound traffic of the CD task, comma-separated; it needs to reach the AWS APIs and the CD image
+	CdEgressCIDRs = os.Getenv("DEFANG_CD_EGRESS_CIDRS")
+	// CdLogDestinationARN is a destination for the CD logs, like a Kinesis stream or a Lambda function
+	CdLogDestinationARN = os.Getenv("DEFANG_CD_LOG_DESTINATION_ARN")
+	// CdLogDestinationRoleARN is the role that CloudWatch Logs assumes to write to CdLogDestinationARN; not needed for Lambda
+	CdLogDestinationRoleARN = os.Getenv("DEFANG_CD_LOG_DESTINATION_ROLE_ARN")
+	// CdLogRetentionDays is how long the CD logs are kept; must be one of the values that CloudWatch Logs supports, or 0
+	// to keep the retention of the CD stack, which is 1 day by default
+	CdLogRetentionDays = pkg.GetenvInt("DEFANG_CD_LOG_RETENTION_DAYS", 0)
+	// CdPermissionsBoundary is the ARN of a policy that is set as the permissions boundary of the CD task role
+	CdPermissionsBoundary = os.Getenv("DEFANG_CD_PERMISSIONS_BOUNDARY")
+	// CdRoleARN is an existing role for the CD task, instead of the one with CdTaskPolicy that is created by the CD stack
+	CdRoleARN = os.Getenv("DEFANG_CD_ROLE_ARN")
+	// CdSpot runs the CD task on Fargate Spot; long deployments can be interrupted, so this can be disabled with DEFANG_CD_SPOT=false.
+	// Empty to keep the setting of the CD stack, which is Spot by default
+	CdSpot = os.Getenv("DEFANG_CD_SPOT")
+	// CdRegion is the region of the CD stack and its bucket; defaults to the region of the AWS profile
+	CdRegion = os.Getenv("DEFANG_CD_REGION")
+	// IgnoreRegionMismatch only warns, instead of failing, when the services were deployed in another region than Region
+	IgnoreRegionMismatch = pkg.GetenvBool("DEFANG_IGNORE_REGION_MISMATCH")
+	// QuotaPolicy is an SSM parameter with the quotas of all projects, like {cpus: 4}; can be the ARN of a parameter shared by the organization
+	QuotaPolicy = os.Getenv("DEFANG_QUOTA_POLICY")
+	// Region is the region of the services; defaults to the CD region
+	Region = os.Getenv("DEFANG_REGION")
+	// SecretsProvider is either "awskms" for the KMS key of the CD stack, or "passphrase" for PULUMI_CONFIG_PASSPHRASE
+	SecretsProvider = pkg.Getenv("DEFANG_SECRETS_PROVIDER", SecretsProviderKms)
+	// SubnetIDs are existing private subnets for the services, comma-separated; the first one is also used for the CD task
+	SubnetIDs = os.Getenv("DEFANG_SUBNET_IDS")
+	// VpcID is an existing VPC for the CD task and the services; tasks won't get a public IP, so it needs a NAT gateway
+	VpcID = os.Getenv("DEFANG_VPC_ID")
+	// Stack allows multiple environments, like staging and production, of the same project in one account
+	Stack = pkg.Getenv("DEFANG_STACK", DefaultStack)
+
+	// From https://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutRetentionPolicy.html
+	validLogRetentionDays = []int{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+	// The stack name is used in resource names and in the domain name
+	validStackName = regexp.MustCompile(`^[a-z][a-z0-9-]{0,19}$`)
 )
@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
+)
+
+const maxDeployments = 100 // each deployment is a download of the state file, so don't go back further than this
+
+// ListDeployments returns the past deployments of the project, newest first, from the versions of the state file in the
+// versioned bucket; limit 0 returns up to maxDeployments
+func (b *ByocAws) ListDeployments(ctx context.Context, limit int) ([]client.Deployment, error) {
+	if limit <= 0 || limit > maxDeployments {
+		limit = maxDeployments
+	}
+	var deployments []client.Deployment
+	err := b.walkDeployments(ctx, func(deployment client.Deployment) bool {
+		deployments = append(deployments, deployment)
+		return len(deployments) < limit
+	})
+	return deployments, err
+}
+
+// GetDeployment returns the deployment with the given etag, from the last maxDeployments deployments of the project
+func (b *ByocAws) GetDeployment(ctx context.Context, etag types.ETag) (*client.Deployment, error) {
+	var found *client.Deployment
+	var count int
+	err := b.walkDeployments(ctx, func(deployment client.Deployment) bool {
+		if deployment.Etag == etag {
+			found = &deployment
+			return false
+		}
+		count++
+		return count < maxDeployments
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("deployment %q not found in the last %d deployments", etag, maxDeployments)
+	}
+	return found, nil
+}
+
+// walkDeployments calls fn for each past deployment, newest first, until it returns false; the state file of each
+// version is only downloaded when it's needed
+func (b *ByocAws) walkDeployments(ctx context.Context, fn func(client.Deployment) bool) error {
+	bucketName, s3Client, err := b.projectBucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	path := b.projectPath()
+	term.Debug(" - Listing versions of", bucketName, path)
+	paginator := s3.NewListObjectVersionsPaginator(s3Client, &s3.ListObjectVersionsInput{
+		Bucket: &bucketName,
+		Prefix: &path,
+	})
+	for paginator.HasMorePages() {
+		lovo, err := paginator.NextPage(ctx)
+		if err != nil {
+			return annotateAwsError(err)
+		}
+		// Versions are returned newest first; delete markers are listed separately
+		for _, version := range lovo.Versions {
+			if version.Key == nil || *version.Key != path {
+				continue
+			}
+			project, err := getProjectVersion(ctx, s3Client, bucketName, path, version.VersionId)
+			if err != nil {
+				return err
+			}
+			deployment := client.Deployment{
+				DeployedBy: project.DeployedBy,
+				Project:    project,
+			}
+			if version.LastModified != nil {
+				deployment.DeployedAt = *version.LastModified
+			}
+			if len(project.Services) > 0 {
+				deployment.Etag = project.Services[0].Etag // same etag for all services
+			}
+			if !fn(deployment) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/defang-io/defang/src/pkg/clouds/aws"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+// checkGpuQuota makes sure the account can run the GPU instances for the services, since the default quota is zero in most accounts
+func (b *ByocAws) checkGpuQuota(ctx context.Context, services []*defangv1.Service) error {
+	vcpus, err := gpuInstanceVCpus(services)
+	if err != nil || vcpus == 0 {
+		return err
+	}
+
+	cfg, err := aws.LoadDefaultConfig(ctx, b.serviceRegion())
+	if err != nil {
+		return annotateAwsError(err)
+	}
+	quota, err := aws.GetServiceQuota(ctx, cfg, ecs.GpuQuotaService, ecs.GpuQuotaCode)
+	if err != nil {
+		// Same as checkFargateQuota: the services might still fit, so don't fail the deployment because of the Service Quotas API
+		term.Warn(" ! Failed to get the GPU instance quota; skipping the check:", annotateAwsError(err))
+		return nil
+	}
+	term.Debugf(" - GPU services need %d vCPUs of G instances; the quota is %v", vcpus, quota)
+	if float64(vcpus) > quota {
+		return fmt.Errorf("the GPU services need %d vCPUs of G instances, but the quota in %s is %v; request an increase at https://console.aws.amazon.com/servicequotas/home/services/%s/quotas/%s",
+			vcpus, cfg.Region, quota, ecs.GpuQuotaService, ecs.GpuQuotaCode)
+	}
+	return nil
+}
+
+// gpuInstanceVCpus returns the number of vCPUs of the GPU instances for all replicas of the services
+func gpuInstanceVCpus(services []*defangv1.Service) (uint32, error) {
+	var vcpus uint32
+	for _, service := range services {
+		if service.Deploy == nil || service.Deploy.Resources == nil || service.Deploy.Resources.Reservations == nil {
+			continue
+		}
+		var gpus uint32
+		for _, device := range service.Deploy.Resources.Reservations.Devices {
+			gpus += max(device.Count, 1)
+		}
+		if gpus == 0 {
+			continue
+		}
+		instance, err := ecs.GetGpuInstance(gpus)
+		if err != nil {
+			return 0, fmt.Errorf("service %q: %w", service.Name, err)
+		}
+		vcpus += instance.VCpus * service.Deploy.Replicas // can be 0
+	}
+	return vcpus, nil
+}
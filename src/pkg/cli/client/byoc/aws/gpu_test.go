@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"testing"
+
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+func TestGpuInstanceVCpus(t *testing.T) {
+	gpuService := func(name string, replicas uint32, counts ...uint32) *defangv1.Service {
+		var devices []*defangv1.Device
+		for _, count := range counts {
+			devices = append(devices, &defangv1.Device{Capabilities: []string{"gpu"}, Count: count})
+		}
+		return &defangv1.Service{Name: name, Deploy: &defangv1.Deploy{
+			Replicas:  replicas,
+			Resources: &defangv1.Resources{Reservations: &defangv1.Resource{Devices: devices}},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		services []*defangv1.Service
+		want     uint32
+		wantErr  bool
+	}{
+		{"no services", nil, 0, false},
+		{"no deploy", []*defangv1.Service{{Name: "web"}}, 0, false},
+		{"no devices", []*defangv1.Service{gpuService("web", 2)}, 0, false},
+		{"one gpu", []*defangv1.Service{gpuService("llm", 1, 1)}, 4, false},
+		{"scaled to zero", []*defangv1.Service{gpuService("llm", 0, 1)}, 0, false},
+		{"replicas", []*defangv1.Service{gpuService("llm", 3, 1)}, 12, false},
+		{"two devices", []*defangv1.Service{gpuService("llm", 1, 1, 1)}, 48, false},
+		{"two services", []*defangv1.Service{gpuService("llm", 1, 1), gpuService("train", 1, 8)}, 100, false},
+		{"too many gpus", []*defangv1.Service{gpuService("train", 1, 16)}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gpuInstanceVCpus(tt.services)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %d vCPUs, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/defang-io/defang/src/pkg"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/region"
+	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
+)
+
+const (
+	bastionImage    = "public.ecr.aws/amazonlinux/amazonlinux:minimal"
+	bastionLifetime = "43200" // seconds; in case the bastion task is not stopped by the CLI
+)
+
+// PortForward forwards the local port to the remote port of a running task of the service, until the context is canceled.
+// If the service doesn't have ECS Exec enabled, it tunnels through a short-lived bastion task in the same subnet instead.
+func (b *ByocAws) PortForward(ctx context.Context, service string, localPort, remotePort int) error {
+	if err := b.setUp(ctx); err != nil {
+		return err
+	}
+
+	taskArn, err := b.findServiceTask(ctx, service)
+	if err != nil {
+		return err
+	}
+	term.Debug(" - Forwarding port", localPort, "to port", remotePort, "of task", *taskArn)
+	isService := func(name string) bool { return isServiceResource(name, service) }
+	err = ecs.StartPortForwarding(ctx, taskArn, isService, "", localPort, remotePort)
+	if !errors.Is(err, ecs.ErrExecuteCommandDisabled) {
+		return annotateAwsError(err)
+	}
+
+	term.Info("ECS Exec is not enabled for service", service+"; starting a bastion task")
+	return annotateAwsError(b.portForwardViaBastion(ctx, taskArn, b.getPrivateFqdn(service), localPort, remotePort))
+}
+
+// bastionStackPrefix returns the prefix of the names of the bastion stacks of the project; stack names can't have underscores
+func (b *ByocAws) bastionStackPrefix() string {
+	if b.pulumiProject == "" {
+		panic("pulumiProject not set")
+	}
+	return fmt.Sprintf("%s-bastion-%s-%s-", CdTaskPrefix, strings.ReplaceAll(b.pulumiProject, "_", "-"), b.pulumiStack)
+}
+
+// tearDownBastions deletes the bastion stacks of the project in the region of the services, in case a port-forward session didn't
+func (b *ByocAws) tearDownBastions(ctx context.Context) error {
+	stacks, err := cfn.ListStacks(ctx, b.serviceRegion(), b.bastionStackPrefix())
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, stack := range stacks {
+		if err := cfn.New(stack, b.serviceRegion()).TearDown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stack, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// portForwardViaBastion forwards the local port to the remote port of host through a bastion task in the subnet of the given task
+func (b *ByocAws) portForwardViaBastion(ctx context.Context, serviceTaskArn ecs.TaskArn, host string, localPort, remotePort int) error {
+	subnetID, err := ecs.GetTaskSubnetID(ctx, serviceTaskArn)
+	if err != nil {
+		return err
+	}
+
+	// Each session has its own bastion stack, so ending it doesn't affect the sessions of other users
+	bastionStackName := b.bastionStackPrefix() + pkg.RandomID()
+	bastion := cfn.New(bastionStackName, region.FromArn(*serviceTaskArn))
+	if err := bastion.PopulateVPCandSubnetID(ctx, "", subnetID); err != nil {
+		return err
+	}
+	// The bastion only needs to reach the service and, over HTTPS, the AWS APIs for ECR, CloudWatch Logs and SSM
+	bastion.Overrides.EgressPorts = []int{443}
+	if remotePort != 443 {
+		bastion.Overrides.EgressPorts = append(bastion.Overrides.EgressPorts, remotePort)
+	}
+	containers := []types.Container{
+		{
+			Image:     bastionImage,
+			Name:      ecs.ContainerName,
+			Cpus:      0.25,
+			Memory:    512_000_000, // 512M
+			Essential: ptr.Bool(true),
+		},
+	}
+	defer func() {
+		// Delete the stack, even if the setup failed halfway; use a fresh context, because ctx may be canceled
+		if err := bastion.TearDown(context.Background()); err != nil {
+			term.Warn("Failed to delete bastion stack", bastionStackName+":", err)
+		}
+	}()
+	if err := bastion.SetUp(ctx, containers); err != nil {
+		return err
+	}
+
+	taskArn, err := bastion.Run(ctx, nil, "sleep", bastionLifetime)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Use a fresh context, because ctx is canceled when the user ends the session
+		if err := bastion.Stop(context.Background(), taskArn); err != nil {
+			term.Warn("Failed to stop bastion task", ecs.GetTaskID(taskArn)+":", err)
+		}
+	}()
+
+	term.Info("Waiting for bastion task", ecs.GetTaskID(taskArn), "to start")
+	if err := ecs.WaitForExecuteCommandAgent(ctx, taskArn, 2*time.Second); err != nil {
+		return err
+	}
+
+	term.Debug(" - Forwarding port", localPort, "to", host, "port", remotePort, "through task", *taskArn)
+	isBastion := func(name string) bool { return name == ecs.ContainerName }
+	return ecs.StartPortForwarding(ctx, taskArn, isBastion, host, localPort, remotePort)
+}
@@ -0,0 +1,140 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/defang-io/defang/src/pkg/clouds/aws"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/quota"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+	"gopkg.in/yaml.v3"
+)
+
+// fargateLimits are the largest Fargate task; see https://docs.aws.amazon.com/AmazonECS/latest/developerguide/task-cpu-memory-error.html
+var fargateLimits = quota.Quotas{
+	Cpus:      ecs.FargateMaxCpus,
+	MemoryMiB: ecs.FargateMaxMemoryMiB,
+}
+
+// parseProjectQuotas parses the x-defang-quotas extension of the Compose file
+func parseProjectQuotas(ext any) (quota.Quotas, error) {
+	data, err := yaml.Marshal(ext)
+	if err != nil {
+		return quota.Quotas{}, err
+	}
+	return quota.Parse(data)
+}
+
+// loadQuotas returns the default quotas with the ones of the project, capped by the policy of the organization, if any
+func (b *ByocAws) loadQuotas(ctx context.Context) (quota.Quotas, error) {
+	quotas := b.quota.Override(b.projectQuotas)
+
+	if QuotaPolicy != "" {
+		cfg, err := aws.LoadDefaultConfig(ctx, b.serviceRegion())
+		if err != nil {
+			return quotas, annotateAwsError(err)
+		}
+		gpo, err := ssm.NewFromConfig(cfg).GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           ptr.String(QuotaPolicy), // can be the ARN of a parameter that is shared with the account
+			WithDecryption: ptr.Bool(true),
+		})
+		if err != nil {
+			return quotas, fmt.Errorf("failed to get the quota policy %q: %w", QuotaPolicy, annotateAwsError(err))
+		}
+		policy, err := quota.Parse([]byte(ptr.ToString(gpo.Parameter.Value)))
+		if err != nil {
+			return quotas, fmt.Errorf("invalid quota policy %q: %w", QuotaPolicy, err)
+		}
+		term.Debugf(" - Quota policy %s: %+v", QuotaPolicy, policy)
+		quotas = quotas.Cap(policy)
+	}
+
+	if err := quotas.CheckLimits(fargateLimits, "Fargate"); err != nil {
+		return quotas, fmt.Errorf("x-defang-quotas: %w", err)
+	}
+	term.Debugf(" - Quotas: %+v", quotas)
+	return quotas, nil
+}
+
+// validateServices checks all services against the quotas and returns all violations at once
+func (b *ByocAws) validateServices(ctx context.Context, services []*defangv1.Service) error {
+	quotas, err := b.loadQuotas(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	if len(services) > quotas.Services {
+		errs = append(errs, fmt.Errorf("maximum number of services reached (max %d)", quotas.Services))
+	}
+	for _, service := range services {
+		errs = append(errs, quotas.Validate(service))
+	}
+	errs = append(errs, b.checkFargateQuota(ctx, services), b.checkGpuQuota(ctx, services))
+	return errors.Join(errs...)
+}
+
+// checkFargateQuota makes sure the account can run the Fargate tasks of all replicas of the services
+func (b *ByocAws) checkFargateQuota(ctx context.Context, services []*defangv1.Service) error {
+	onDemand, spot := fargateVCpus(services)
+	if onDemand == 0 && spot == 0 {
+		return nil
+	}
+
+	cfg, err := aws.LoadDefaultConfig(ctx, b.serviceRegion())
+	if err != nil {
+		return annotateAwsError(err)
+	}
+	var errs []error
+	for _, check := range []struct {
+		vcpus     float64
+		quotaCode string
+		name      string
+	}{
+		{onDemand, ecs.FargateOnDemandQuotaCode, "Fargate On-Demand"},
+		{spot, ecs.FargateSpotQuotaCode, "Fargate Spot"},
+	} {
+		if check.vcpus == 0 {
+			continue
+		}
+		quota, err := aws.GetServiceQuota(ctx, cfg, ecs.FargateQuotaService, check.quotaCode)
+		if err != nil {
+			// The services might still fit, so don't fail the deployment because of the Service Quotas API
+			term.Warn(" ! Failed to get the", check.name, "vCPU quota; skipping the check:", annotateAwsError(err))
+			continue
+		}
+		term.Debugf(" - Services need %v vCPUs of %s; the quota is %v", check.vcpus, check.name, quota)
+		if check.vcpus > quota {
+			errs = append(errs, fmt.Errorf("the services need %v vCPUs of %s, but the quota in %s is %v; request an increase at https://console.aws.amazon.com/servicequotas/home/services/%s/quotas/%s",
+				check.vcpus, check.name, cfg.Region, quota, ecs.FargateQuotaService, check.quotaCode))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fargateVCpus returns the number of vCPUs of all replicas of the services that run on Fargate, on-demand and Spot
+func fargateVCpus(services []*defangv1.Service) (onDemand, spot float64) {
+	for _, service := range services {
+		cpus, replicas := float64(ecs.FargateMinCpus), uint32(1)
+		if service.Deploy != nil {
+			replicas = service.Deploy.Replicas // can be 0
+			if reservations := service.Deploy.GetResources().GetReservations(); reservations != nil {
+				if len(reservations.Devices) > 0 {
+					continue // GPU services run on EC2; see checkGpuQuota
+				}
+				cpus = max(float64(reservations.Cpus), cpus)
+			}
+		}
+		vcpus := cpus * float64(replicas)
+		if capacity := service.Capacity; capacity != nil && capacity.OnDemand == 0 {
+			spot += vcpus
+		} else {
+			onDemand += vcpus // mixed capacity counts as on-demand, to be safe
+		}
+	}
+	return onDemand, spot
+}
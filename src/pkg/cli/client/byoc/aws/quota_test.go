@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/defang-io/defang/src/pkg/quota"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+func TestParseProjectQuotas(t *testing.T) {
+	got, err := parseProjectQuotas(map[string]any{"cpus": 2, "replicas": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (quota.Quotas{Cpus: 2, Replicas: 3}); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err := parseProjectQuotas(map[string]any{"cpu": 2}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestFargateVCpus(t *testing.T) {
+	services := []*defangv1.Service{
+		{Name: "default"}, // 0.25 vCPU
+		{Name: "replicas", Deploy: &defangv1.Deploy{Replicas: 3, Resources: &defangv1.Resources{Reservations: &defangv1.Resource{Cpus: 2}}}},
+		{Name: "spot", Capacity: &defangv1.Capacity{Spot: 1}, Deploy: &defangv1.Deploy{Replicas: 2, Resources: &defangv1.Resources{Reservations: &defangv1.Resource{Cpus: 1}}}},
+		{Name: "mixed", Capacity: &defangv1.Capacity{Spot: 3, OnDemand: 1}, Deploy: &defangv1.Deploy{Replicas: 1, Resources: &defangv1.Resources{Reservations: &defangv1.Resource{Cpus: 4}}}},
+		{Name: "scaled-to-zero", Deploy: &defangv1.Deploy{Replicas: 0, Resources: &defangv1.Resources{Reservations: &defangv1.Resource{Cpus: 8}}}},
+		{Name: "gpu", Deploy: &defangv1.Deploy{Resources: &defangv1.Resources{Reservations: &defangv1.Resource{Cpus: 4, Devices: []*defangv1.Device{{Count: 1}}}}}},
+	}
+	onDemand, spot := fargateVCpus(services)
+	if onDemand != 10.25 {
+		t.Errorf("expected 10.25 on-demand vCPUs, got %v", onDemand)
+	}
+	if spot != 2 {
+		t.Errorf("expected 2 Spot vCPUs, got %v", spot)
+	}
+}
@@ -0,0 +1,60 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+// ServiceStats returns the latest Container Insights stats for each service; requires Container Insights on the cluster.
+func (b *ByocAws) ServiceStats(ctx context.Context) (map[string]client.ServiceStats, error) {
+	if err := b.setUp(ctx); err != nil {
+		return nil, err
+	}
+
+	serviceInfos, err := b.GetServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	services := make(map[string]*defangv1.ServiceInfo, len(serviceInfos.Services))
+	for _, si := range serviceInfos.Services {
+		services[si.Service.Name] = si
+	}
+
+	stats := make(map[string]client.ServiceStats)
+	for _, clusterName := range b.getClusterNames() {
+		logGroupARN := b.serviceDriver().MakeARN("logs", "log-group:"+ecs.ContainerInsightsLogGroup(clusterName))
+		// Container Insights sends performance logs every minute
+		serviceStats, err := ecs.QueryServiceStats(ctx, logGroupARN, time.Now().Add(-3*time.Minute))
+		if err != nil {
+			var resourceNotFound *types.ResourceNotFoundException
+			if errors.As(err, &resourceNotFound) {
+				term.Debug(" - No Container Insights for cluster", clusterName)
+				continue
+			}
+			return nil, annotateAwsError(err)
+		}
+		for _, ss := range serviceStats {
+			name := matchServiceName(ss.ServiceName, services)
+			if name == "" {
+				continue
+			}
+			// Events are oldest first, so the latest stats win
+			stats[name] = client.ServiceStats{
+				CPUReserved:     ss.CpuReserved / 1024, // CPU units to vCPU
+				CPUUtilized:     ss.CpuUtilized / 1024,
+				MemoryReserved:  ss.MemoryReserved,
+				MemoryUtilized:  ss.MemoryUtilized,
+				DesiredReplicas: uint32(ss.DesiredTaskCount),
+				RunningReplicas: uint32(ss.RunningTaskCount),
+			}
+		}
+	}
+	return stats, nil
+}
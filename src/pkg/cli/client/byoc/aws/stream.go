@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,22 +24,22 @@ type byocServerStream struct {
 	errCh    <-chan error
 	etag     string
 	response *defangv1.TailResponse
-	service  string
+	services []string
 	stream   ecs.EventStream
 }
 
-func newByocServerStream(ctx context.Context, stream ecs.EventStream, etag, service string) *byocServerStream {
+func newByocServerStream(ctx context.Context, stream ecs.EventStream, etag string, services []string) *byocServerStream {
 	var errCh <-chan error
 	if errch, ok := stream.(hasErrCh); ok {
 		errCh = errch.Errs()
 	}
 
 	return &byocServerStream{
-		ctx:     ctx,
-		errCh:   errCh,
-		etag:    etag,
-		stream:  stream,
-		service: service,
+		ctx:      ctx,
+		errCh:    errCh,
+		etag:     etag,
+		stream:   stream,
+		services: services,
 	}
 }
 
@@ -129,7 +130,7 @@ func (bs *byocServerStream) parseEvents(e types.StartLiveTailResponseStream) ([]
 	if bs.etag != "" && bs.etag != bs.response.Etag {
 		return nil, nil // TODO: filter these out using the AWS StartLiveTail API
 	}
-	if bs.service != "" && bs.service != bs.response.Service {
+	if len(bs.services) > 0 && !slices.Contains(bs.services, bs.response.Service) {
 		return nil, nil // TODO: filter these out using the AWS StartLiveTail API
 	}
 	entries := make([]*defangv1.LogEntry, len(events))
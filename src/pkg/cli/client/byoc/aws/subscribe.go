@@ -0,0 +1,325 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/defang-io/defang/src/pkg"
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// subscribeStream is a channel-based implementation of the connect-like ServerStream for Subscribe
+type subscribeStream struct {
+	cancel   context.CancelFunc
+	ch       chan *defangv1.SubscribeResponse
+	ctx      context.Context
+	err      error
+	response *defangv1.SubscribeResponse
+}
+
+func newSubscribeStream(ctx context.Context) *subscribeStream {
+	ctx, cancel := context.WithCancel(ctx)
+	return &subscribeStream{
+		cancel: cancel,
+		ch:     make(chan *defangv1.SubscribeResponse),
+		ctx:    ctx,
+	}
+}
+
+var _ client.ServerStream[defangv1.SubscribeResponse] = (*subscribeStream)(nil)
+
+func (ss *subscribeStream) Close() error {
+	ss.cancel()
+	return nil
+}
+
+func (ss *subscribeStream) Err() error {
+	if ss.err == io.EOF {
+		return nil // same as the original gRPC/connect server stream
+	}
+	return annotateAwsError(ss.err)
+}
+
+func (ss *subscribeStream) Msg() *defangv1.SubscribeResponse {
+	return ss.response
+}
+
+func (ss *subscribeStream) Receive() bool {
+	response, ok := <-ss.ch // blocking
+	if !ok {
+		return false // err was set before the channel was closed
+	}
+	ss.response = response
+	return true
+}
+
+// send is called by the producer goroutine; returns false if the stream was closed
+func (ss *subscribeStream) send(response *defangv1.SubscribeResponse) bool {
+	select {
+	case ss.ch <- response:
+		return true
+	case <-ss.ctx.Done():
+		return false
+	}
+}
+
+// close is called by the producer goroutine when it's done; err is io.EOF on success
+func (ss *subscribeStream) close(err error) {
+	ss.err = err
+	close(ss.ch)
+}
+
+func (b *ByocAws) Subscribe(ctx context.Context, req *defangv1.SubscribeRequest) (client.ServerStream[defangv1.SubscribeResponse], error) {
+	if err := b.setUp(ctx); err != nil {
+		return nil, err
+	}
+
+	// When waiting for a deployment, the exit of the CD task determines the outcome
+	var taskArn ecs.TaskArn
+	if req.Etag != "" {
+		var err error
+		if pkg.IsValidRandomID(req.Etag) {
+			// Find the CD task by its tag, in case the deployment was started from another shell
+			if taskArn, err = b.findCdTask(ctx, req.Etag); err != nil {
+				return nil, err
+			}
+			if taskArn == nil {
+				return nil, fmt.Errorf("no CD task found for deployment ID %q; it might have finished more than an hour ago", req.Etag)
+			}
+		} else {
+			// Assume "etag" is a task ID
+			if taskArn, err = b.driver.GetTaskArn(req.Etag); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Start with the services from the project.pb in S3; this fails if nothing was deployed yet
+	services := make(map[string]*defangv1.ServiceInfo)
+	if serviceInfos, err := b.GetServices(ctx); err != nil {
+		term.Debug(" - Failed to get services:", err)
+	} else {
+		for _, si := range serviceInfos.Services {
+			services[si.Service.Name] = si
+		}
+	}
+
+	ss := newSubscribeStream(ctx)
+
+	// The ECS service events are sent to a log group by EventBridge
+	ecsEvents := ecs.LogGroupInput{LogGroupARN: b.serviceDriver().MakeARN("logs", "log-group:"+b.stackDir("ecs"))} // must match logic in ecs/common.ts
+	tail := func() (ecs.EventStream, error) {
+		return ecs.TailLogGroups(ss.ctx, time.Now(), ecsEvents)
+	}
+	eventStream, err := tail()
+	if err != nil {
+		if taskArn == nil {
+			ss.Close()
+			return nil, annotateAwsError(err)
+		}
+		term.Debug(" - Failed to tail ECS events:", err) // we can still wait for the CD task
+		eventStream = nil
+	}
+
+	var cdDone chan error // nil channel blocks forever
+	if taskArn != nil {
+		cdDone = make(chan error, 1)
+		go func() {
+			cdDone <- ecs.WaitForTask(ss.ctx, taskArn, 3*time.Second)
+		}()
+	}
+
+	go b.followServiceEvents(ss, req, cdDone, services, eventStream, tail)
+	return ss, nil
+}
+
+// followServiceEvents sends the service updates until the CD task is done, if any; tail is used to reconnect the event
+// stream when it ends before that, like when the live tail session times out.
+func (b *ByocAws) followServiceEvents(ss *subscribeStream, req *defangv1.SubscribeRequest, cdDone <-chan error, services map[string]*defangv1.ServiceInfo, eventStream ecs.EventStream, tail func() (ecs.EventStream, error)) {
+	var events <-chan types.StartLiveTailResponseStream
+	var errCh <-chan error
+	follow := func(stream ecs.EventStream) {
+		eventStream, events, errCh = stream, nil, nil
+		if stream != nil {
+			events = stream.Events()
+			if errch, ok := stream.(hasErrCh); ok {
+				errCh = errch.Errs()
+			}
+		}
+	}
+	follow(eventStream)
+	defer func() {
+		if eventStream != nil {
+			eventStream.Close()
+		}
+	}()
+
+	// reconnect returns false if the stream was closed because the event stream ended with err and can't be resumed
+	reconnect := func(err error) bool {
+		eventStream.Close()
+		follow(nil)
+		select {
+		case <-ss.ctx.Done():
+			ss.close(ss.ctx.Err())
+			return false
+		case <-time.After(time.Second): // don't hammer the API if the stream keeps ending
+		}
+		term.Debug(" - ECS event stream ended; reconnecting:", err)
+		stream, terr := tail()
+		if terr == nil {
+			follow(stream)
+			return true
+		}
+		if cdDone != nil {
+			term.Debug(" - Failed to tail ECS events:", terr) // we can still wait for the CD task
+			return true
+		}
+		ss.close(terr)
+		return false
+	}
+
+	for {
+		select {
+		case <-ss.ctx.Done():
+			ss.close(ss.ctx.Err())
+			return
+
+		case err := <-errCh:
+			if !reconnect(err) {
+				return
+			}
+
+		case err := <-cdDone:
+			if err != io.EOF {
+				ss.close(err)
+				return
+			}
+			// The CD task only exits after Pulumi has waited for the ECS services to become stable
+			serviceInfos, err := b.GetServices(ss.ctx)
+			if err != nil {
+				ss.close(err)
+				return
+			}
+			var response defangv1.SubscribeResponse
+			for _, si := range serviceInfos.Services {
+				if req.Service != "" && req.Service != si.Service.Name {
+					continue
+				}
+				si.Etag = req.Etag
+				si.Status = "DEPLOYMENT_COMPLETED"
+				response.Services = append(response.Services, si)
+			}
+			if ss.send(&response) {
+				err = io.EOF
+			} else {
+				err = ss.ctx.Err()
+			}
+			ss.close(err)
+			return
+
+		case e := <-events:
+			logEvents, err := ecs.GetLogEvents(e)
+			if err != nil { // io.EOF if the stream was closed
+				if !reconnect(err) {
+					return
+				}
+				continue
+			}
+			var response defangv1.SubscribeResponse
+			for _, event := range logEvents {
+				si := applyServiceEvent(ss.ctx, services, *event.Message)
+				if si == nil || (req.Service != "" && req.Service != si.Service.Name) {
+					continue
+				}
+				response.Services = append(response.Services, proto.Clone(si).(*defangv1.ServiceInfo))
+			}
+			if len(response.Services) > 0 && !ss.send(&response) {
+				ss.close(ss.ctx.Err())
+				return
+			}
+		}
+	}
+}
+
+// applyServiceEvent updates the service info for the service in the ECS event; returns nil if the event was ignored.
+func applyServiceEvent(ctx context.Context, services map[string]*defangv1.ServiceInfo, message string) *defangv1.ServiceInfo {
+	event, err := ecs.ParseEvent([]byte(message))
+	if err != nil {
+		term.Debug(" - Ignoring event:", err)
+		return nil
+	}
+	ecsService := event.ServiceName()
+	si := services[matchServiceName(ecsService, services)]
+	if si == nil {
+		return nil // not one of our services, or a new service that's not in project.pb yet
+	}
+	if state := serviceStateFromEvent(event); state != "" {
+		si.Status = state
+	}
+	if running, desired, err := ecs.GetServiceReplicas(ctx, event.ClusterArn(), ecsService); err != nil {
+		term.Debug(" - Failed to get replicas for service", ecsService, err)
+	} else {
+		si.RunningReplicas = uint32(running)
+		si.DesiredReplicas = uint32(desired)
+	}
+	return si
+}
+
+// matchServiceName returns the name of the service that the ECS service was created for, or "" if none matches.
+func matchServiceName(ecsService string, services map[string]*defangv1.ServiceInfo) string {
+	var match string
+	for name := range services {
+		if len(name) <= len(match) {
+			continue // prefer the longest match
+		}
+		if isServiceResource(ecsService, name) {
+			match = name
+		}
+	}
+	return match
+}
+
+// isServiceResource returns true if the ECS resource name is the service name, optionally with the suffix that Pulumi
+// appends, like "app-1234567" or "app_etag"; unlike a plain prefix match, "app" doesn't match "app-worker-1234567".
+func isServiceResource(resourceName, service string) bool {
+	if resourceName == service {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(resourceName, service+"-"); ok {
+		return isPulumiSuffix(suffix)
+	}
+	if suffix, ok := strings.CutPrefix(resourceName, service+"_"); ok {
+		return pkg.IsValidRandomID(suffix)
+	}
+	return false
+}
+
+// isPulumiSuffix returns true if s is the random suffix of an auto-named Pulumi resource, which is 7 hex digits
+func isPulumiSuffix(s string) bool {
+	return len(s) == 7 && strings.Trim(s, "0123456789abcdef") == ""
+}
+
+// serviceStateFromEvent returns the service state for the ECS event, or "" if the event doesn't change the state.
+func serviceStateFromEvent(event *ecs.Event) string {
+	if event.DetailType != "ECS Deployment State Change" {
+		return "" // task state changes and service actions only affect the replica count
+	}
+	switch event.Detail.EventName {
+	case "SERVICE_DEPLOYMENT_IN_PROGRESS":
+		return "DEPLOYMENT_PENDING"
+	case "SERVICE_DEPLOYMENT_COMPLETED":
+		return "DEPLOYMENT_COMPLETED"
+	case "SERVICE_DEPLOYMENT_FAILED":
+		return "DEPLOYMENT_FAILED"
+	default:
+		return ""
+	}
+}
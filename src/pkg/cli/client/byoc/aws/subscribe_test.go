@@ -0,0 +1,45 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+type fakeEventStream struct {
+	ch chan types.StartLiveTailResponseStream
+}
+
+func (f *fakeEventStream) Close() error { return nil }
+
+func (f *fakeEventStream) Events() <-chan types.StartLiveTailResponseStream { return f.ch }
+
+func TestFollowServiceEventsReconnects(t *testing.T) {
+	ss := newSubscribeStream(context.Background())
+	defer ss.Close()
+
+	first := &fakeEventStream{ch: make(chan types.StartLiveTailResponseStream)}
+	close(first.ch) // the live tail session ended before the CD task
+	reconnected := make(chan struct{})
+	tail := func() (ecs.EventStream, error) {
+		close(reconnected)
+		return &fakeEventStream{ch: make(chan types.StartLiveTailResponseStream)}, nil
+	}
+	cdDone := make(chan error, 1)
+	b := &ByocAws{}
+	go b.followServiceEvents(ss, &defangv1.SubscribeRequest{}, cdDone, nil, first, tail)
+
+	<-reconnected
+	cdErr := errors.New("CD task failed")
+	cdDone <- cdErr
+	if ss.Receive() {
+		t.Fatal("expected no responses")
+	}
+	if err := ss.Err(); err == nil || err.Error() != cdErr.Error() {
+		t.Errorf("expected the stream to end with the CD task, got %v", err)
+	}
+}
@@ -2,8 +2,10 @@ package client
 
 import (
 	"context"
+	"time"
 
 	compose "github.com/compose-spec/compose-go/v2/types"
+	"github.com/defang-io/defang/src/pkg/registry"
 	"github.com/defang-io/defang/src/pkg/types"
 	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
 )
@@ -22,11 +24,17 @@ type ProjectLoader interface {
 
 type Client interface {
 	// Promote(google.protobuf.Empty) returns (google.protobuf.Empty);
-	// Subscribe(context.Context, *v1.SubscribeRequest) (*v1.SubscribeResponse, error)
 	// Update(context.Context, *v1.Service) (*v1.ServiceInfo, error)
 	AgreeToS(context.Context) error
 	BootstrapCommand(context.Context, string) (types.ETag, error)
+	BootstrapDrift(context.Context) ([]CdStackDrift, error)
 	BootstrapList(context.Context) ([]string, error)
+	BootstrapDiscard(context.Context, *CdStackPlan) error
+	BootstrapPreview(context.Context) (*CdStackPlan, error)
+	BootstrapQueryLogs(ctx context.Context, taskID string) ([]CdLogEvent, error)
+	BootstrapSetUp(context.Context, *CdStackPlan) error
+	BootstrapTasks(context.Context) ([]CdTask, error)
+	BootstrapUnlock(context.Context) error
 	CheckLoginAndToS(context.Context) error
 	CreateUploadURL(context.Context, *defangv1.UploadURLRequest) (*defangv1.UploadURLResponse, error)
 	DelegateSubdomainZone(context.Context, *defangv1.DelegateSubdomainZoneRequest) (*defangv1.DelegateSubdomainZoneResponse, error)
@@ -36,17 +44,24 @@ type Client interface {
 	DeleteSubdomainZone(context.Context) error
 	Deploy(context.Context, *defangv1.DeployRequest) (*defangv1.DeployResponse, error)
 	Destroy(context.Context) (types.ETag, error)
+	Exec(ctx context.Context, service string, args ...string) error
 	GenerateFiles(context.Context, *defangv1.GenerateFilesRequest) (*defangv1.GenerateFilesResponse, error)
 	Get(context.Context, *defangv1.ServiceID) (*defangv1.ServiceInfo, error)
 	GetDelegateSubdomainZone(context.Context) (*defangv1.DelegateSubdomainZoneResponse, error)
+	GetDeployment(ctx context.Context, etag types.ETag) (*Deployment, error)
 	GetServices(context.Context) (*defangv1.ListServicesResponse, error)
 	GetVersions(context.Context) (*defangv1.Version, error)
 	ListConfig(context.Context) (*defangv1.Secrets, error)
+	ListDeployments(ctx context.Context, limit int) ([]Deployment, error)
+	PortForward(ctx context.Context, service string, localPort, remotePort int) error
 	Publish(context.Context, *defangv1.PublishRequest) error
 	PutConfig(context.Context, *defangv1.SecretValue) error
+	PutRegistryCredentials(context.Context, map[string]registry.Credentials) (map[string]string, error)
 	Restart(context.Context, ...string) (types.ETag, error)
 	RevokeToken(context.Context) error
 	ServiceDNS(name string) string
+	ServiceStats(context.Context) (map[string]ServiceStats, error)
+	Subscribe(context.Context, *defangv1.SubscribeRequest) (ServerStream[defangv1.SubscribeResponse], error)
 	Tail(context.Context, *defangv1.TailRequest) (ServerStream[defangv1.TailResponse], error)
 	TearDown(context.Context) error
 	Token(context.Context, *defangv1.TokenRequest) (*defangv1.TokenResponse, error)
@@ -57,6 +72,66 @@ type Client interface {
 	LoadProjectName() (string, error) // TODO: should probably be a private method
 }
 
+// ServiceStats are the latest resource usage stats of a service, summed over all its replicas
+type ServiceStats struct {
+	CPUReserved     float64 // vCPU
+	CPUUtilized     float64 // vCPU
+	MemoryReserved  float64 // MiB
+	MemoryUtilized  float64 // MiB
+	DesiredReplicas uint32
+	RunningReplicas uint32
+}
+
+// CdTask is a running or recently stopped CD task
+type CdTask struct {
+	ID        string
+	Command   string
+	Etag      types.ETag // deployment ID, if any
+	Project   string
+	Status    string
+	Reason    string // why the task stopped
+	StartedAt time.Time
+	StartedBy string
+	StoppedAt time.Time // zero if the task is still running
+}
+
+// CdLogEvent is a log line of a CD task
+type CdLogEvent struct {
+	Stream    string // log stream, one per container
+	Timestamp time.Time
+	Message   string
+}
+
+// CdStackChange is a change that setting up the CD stack would make to one of its resources
+type CdStackChange struct {
+	Action      string // Add, Modify, Remove, ...
+	Resource    string
+	Type        string
+	Replacement string // True, False, or Conditional; only for Modify
+}
+
+// CdStackPlan is the set of changes that setting up the CD stack would make; it's kept until it's applied or discarded
+type CdStackPlan struct {
+	ID      string // empty if there are no changes
+	Changes []CdStackChange
+}
+
+// CdStackDrift is a resource of the CD stack that was changed outside of the CD setup
+type CdStackDrift struct {
+	Resource   string
+	Type       string
+	Status     string   // MODIFIED or DELETED
+	Properties []string // paths of the properties that differ
+}
+
+// Deployment is a past deployment of the project
+type Deployment struct {
+	Etag       types.ETag
+	DeployedAt time.Time
+	DeployedBy string
+	Project    *defangv1.ListServicesResponse // services as they were deployed
+}
+
 type Property struct {
 	Name  string
 	Value any
@@ -14,6 +14,7 @@ import (
 	"github.com/bufbuild/connect-go"
 	compose "github.com/compose-spec/compose-go/v2/types"
 	"github.com/defang-io/defang/src/pkg/auth"
+	"github.com/defang-io/defang/src/pkg/registry"
 	"github.com/defang-io/defang/src/pkg/term"
 	"github.com/defang-io/defang/src/pkg/types"
 	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
@@ -160,6 +161,30 @@ func (g *GrpcClient) Tail(ctx context.Context, req *defangv1.TailRequest) (Serve
 	return g.client.Tail(ctx, &connect.Request[defangv1.TailRequest]{Msg: req})
 }
 
+func (g *GrpcClient) Subscribe(ctx context.Context, req *defangv1.SubscribeRequest) (ServerStream[defangv1.SubscribeResponse], error) {
+	return g.client.Subscribe(ctx, &connect.Request[defangv1.SubscribeRequest]{Msg: req})
+}
+
+func (g *GrpcClient) Exec(context.Context, string, ...string) error {
+	return errors.New("the exec command is not valid for the Defang provider")
+}
+
+func (g *GrpcClient) GetDeployment(context.Context, types.ETag) (*Deployment, error) {
+	return nil, errors.New("the deployment history is not available for the Defang provider")
+}
+
+func (g *GrpcClient) ListDeployments(context.Context, int) ([]Deployment, error) {
+	return nil, errors.New("the deployment history is not available for the Defang provider")
+}
+
+func (g *GrpcClient) PortForward(context.Context, string, int, int) error {
+	return errors.New("the port-forward command is not valid for the Defang provider")
+}
+
+func (g *GrpcClient) ServiceStats(context.Context) (map[string]ServiceStats, error) {
+	return nil, errors.New("service stats are not available for the Defang provider")
+}
+
 func (g *GrpcClient) BootstrapCommand(ctx context.Context, command string) (types.ETag, error) {
 	return "", errors.New("the bootstrap command is not valid for the Defang provider")
 }
@@ -217,10 +242,42 @@ func (g *GrpcClient) TearDown(ctx context.Context) error {
 	return errors.New("the teardown command is not valid for the Defang provider")
 }
 
+func (g *GrpcClient) BootstrapDrift(context.Context) ([]CdStackDrift, error) {
+	return nil, errors.New("this command is not valid for the Defang provider")
+}
+
 func (g *GrpcClient) BootstrapList(context.Context) ([]string, error) {
 	return nil, errors.New("this command is not valid for the Defang provider")
 }
 
+func (g *GrpcClient) BootstrapDiscard(context.Context, *CdStackPlan) error {
+	return errors.New("this command is not valid for the Defang provider")
+}
+
+func (g *GrpcClient) BootstrapPreview(context.Context) (*CdStackPlan, error) {
+	return nil, errors.New("this command is not valid for the Defang provider")
+}
+
+func (g *GrpcClient) BootstrapQueryLogs(context.Context, string) ([]CdLogEvent, error) {
+	return nil, errors.New("this command is not valid for the Defang provider")
+}
+
+func (g *GrpcClient) PutRegistryCredentials(context.Context, map[string]registry.Credentials) (map[string]string, error) {
+	return nil, errors.New("private registry credentials are only supported with bring-your-own-cloud")
+}
+
+func (g *GrpcClient) BootstrapSetUp(context.Context, *CdStackPlan) error {
+	return errors.New("this command is not valid for the Defang provider")
+}
+
+func (g *GrpcClient) BootstrapTasks(context.Context) ([]CdTask, error) {
+	return nil, errors.New("this command is not valid for the Defang provider")
+}
+
+func (g *GrpcClient) BootstrapUnlock(context.Context) error {
+	return errors.New("this command is not valid for the Defang provider")
+}
+
 func (g *GrpcClient) Restart(ctx context.Context, names ...string) (types.ETag, error) {
 	// For now, we'll just get the service info and pass it back to Deploy as-is.
 	services := make([]*defangv1.Service, 0, len(names))
@@ -26,3 +26,7 @@ func (m MockClient) ServiceDNS(service string) string {
 func (m MockClient) LoadProject() (*compose.Project, error) {
 	return m.Project, nil
 }
+
+func (m MockClient) GetServices(ctx context.Context) (*defangv1.ListServicesResponse, error) {
+	return &defangv1.ListServicesResponse{}, nil // nothing deployed yet
+}
@@ -74,7 +74,15 @@ func warnf(format string, args ...interface{}) {
 	term.HadWarnings = true
 }
 
-func getRemoteBuildContext(ctx context.Context, client client.Client, name string, build *compose.BuildConfig, force bool) (string, error) {
+type UploadMode int
+
+const (
+	UploadModeDigest UploadMode = iota // calculate the digest and upload the build context
+	UploadModeForce                    // upload the build context without digest, to force a build
+	UploadModeIgnore                   // only calculate the digest; don't upload anything
+)
+
+func getRemoteBuildContext(ctx context.Context, client client.Client, name string, build *compose.BuildConfig, upload UploadMode) (string, error) {
 	root, err := filepath.Abs(build.Context)
 	if err != nil {
 		return "", fmt.Errorf("invalid build context: %w", err)
@@ -87,13 +95,17 @@ func getRemoteBuildContext(ctx context.Context, client client.Client, name strin
 	}
 
 	var digest string
-	if !force {
+	if upload != UploadModeForce {
 		// Calculate the digest of the tarball and pass it to the fabric controller (to avoid building the same image twice)
 		sha := sha256.Sum256(buffer.Bytes())
 		digest = "sha256-" + base64.StdEncoding.EncodeToString(sha[:]) // same as Nix
 		term.Debug(" - Digest:", digest)
 	}
 
+	if upload == UploadModeIgnore {
+		return digest, nil
+	}
+
 	if DoDryRun {
 		return root, nil
 	}
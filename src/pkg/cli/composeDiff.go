@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/bufbuild/connect-go"
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+	"github.com/muesli/termenv"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrDeploymentCanceled = errors.New("deployment canceled")
+
+type DiffAction string
+
+const (
+	DiffActionAdd       DiffAction = "+"
+	DiffActionChange    DiffAction = "~"
+	DiffActionRemove    DiffAction = "-"
+	DiffActionUnchanged DiffAction = " "
+)
+
+// ServiceDiff is the planned change to a single service
+type ServiceDiff struct {
+	Name    string
+	Action  DiffAction
+	Changes []string // human-readable changes, only for DiffActionChange
+}
+
+// ComposeDiff shows the changes that deploying the Compose project would make to the deployed services
+func ComposeDiff(ctx context.Context, c client.Client) ([]ServiceDiff, error) {
+	project, err := c.LoadProject()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateProject(project); err != nil {
+		return nil, &ComposeError{err}
+	}
+
+	// Only calculate the digests of the build contexts; there's no need to upload anything
+	services, err := convertServices(ctx, c, project.Services, UploadModeIgnore)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := getPlan(ctx, c, services)
+	if err != nil {
+		return nil, err
+	}
+	printPlan(plan)
+	return plan, nil
+}
+
+// confirmPlan shows the plan for deploying the services and asks the user to confirm it
+func confirmPlan(ctx context.Context, c client.Client, services []*defangv1.Service) error {
+	plan, err := getPlan(ctx, c, services)
+	if err != nil {
+		return err
+	}
+	printPlan(plan)
+
+	var confirmed bool
+	if err := survey.AskOne(&survey.Confirm{
+		Message: "Do you want to deploy these changes?",
+	}, &confirmed, nil); err != nil {
+		return err
+	}
+	if !confirmed {
+		return ErrDeploymentCanceled
+	}
+	return nil
+}
+
+func getPlan(ctx context.Context, c client.Client, services []*defangv1.Service) ([]ServiceDiff, error) {
+	deployed, err := getDeployedServices(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return diffServices(deployed, services), nil
+}
+
+// getDeployedServices returns the services that are currently deployed; none if nothing was deployed yet
+func getDeployedServices(ctx context.Context, c client.Client) ([]*defangv1.Service, error) {
+	var deployed []*defangv1.Service
+	serviceList, err := c.GetServices(ctx)
+	if err != nil {
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			return nil, err
+		}
+		term.Debug(" - No services deployed yet:", err)
+	} else {
+		for _, si := range serviceList.Services {
+			deployed = append(deployed, si.Service)
+		}
+	}
+	return deployed, nil
+}
+
+// diffServices compares the deployed services with the new services, sorted by name
+func diffServices(deployed, services []*defangv1.Service) []ServiceDiff {
+	current := make(map[string]*defangv1.Service, len(deployed))
+	for _, service := range deployed {
+		current[service.Name] = service
+	}
+
+	var plan []ServiceDiff
+	for _, service := range services {
+		deployedService, ok := current[service.Name]
+		if !ok {
+			plan = append(plan, ServiceDiff{Name: service.Name, Action: DiffActionAdd})
+			continue
+		}
+		delete(current, service.Name)
+		changes := diffService(deployedService, service)
+		action := DiffActionUnchanged
+		if len(changes) > 0 {
+			action = DiffActionChange
+		}
+		plan = append(plan, ServiceDiff{Name: service.Name, Action: action, Changes: changes})
+	}
+	for name := range current {
+		plan = append(plan, ServiceDiff{Name: name, Action: DiffActionRemove})
+	}
+
+	sort.Slice(plan, func(i, j int) bool { return plan[i].Name < plan[j].Name })
+	return plan
+}
+
+// diffService returns the human-readable changes between the deployed and the new version of a service
+func diffService(before, after *defangv1.Service) []string {
+	var changes []string
+	changed := func(field string, from, to any) {
+		changes = append(changes, fmt.Sprintf("%s: %v → %v", field, orDash(fmt.Sprint(from)), orDash(fmt.Sprint(to))))
+	}
+
+	if before.Image != after.Image {
+		changed("image", before.Image, after.Image)
+	}
+
+	switch {
+	case before.Build == nil && after.Build != nil:
+		changes = append(changes, "build: added")
+	case before.Build != nil && after.Build == nil:
+		changes = append(changes, "build: removed")
+	case before.Build != nil && after.Build != nil:
+		if !sameBuildContext(before.Build.Context, after.Build.Context) {
+			changes = append(changes, "build: context digest changed")
+		}
+		if before.Build.Dockerfile != after.Build.Dockerfile {
+			changed("build dockerfile", before.Build.Dockerfile, after.Build.Dockerfile)
+		}
+		if before.Build.Target != after.Build.Target {
+			changed("build target", before.Build.Target, after.Build.Target)
+		}
+		changes = append(changes, diffKeys("build arg", before.Build.Args, after.Build.Args)...)
+	}
+
+	changes = append(changes, diffKeys("environment", before.Environment, after.Environment)...)
+	changes = append(changes, diffSets("config", secretNames(before.Secrets), secretNames(after.Secrets))...)
+	changes = append(changes, diffSets("port", portNames(before.Ports), portNames(after.Ports))...)
+
+	if before.Deploy.GetReplicas() != after.Deploy.GetReplicas() {
+		changed("replicas", before.Deploy.GetReplicas(), after.Deploy.GetReplicas())
+	}
+	beforeRes, afterRes := before.Deploy.GetResources().GetReservations(), after.Deploy.GetResources().GetReservations()
+	if beforeRes.GetCpus() != afterRes.GetCpus() {
+		changed("cpus", beforeRes.GetCpus(), afterRes.GetCpus())
+	}
+	if beforeRes.GetMemory() != afterRes.GetMemory() {
+		changed("memory", fmt.Sprintf("%gMiB", beforeRes.GetMemory()), fmt.Sprintf("%gMiB", afterRes.GetMemory()))
+	}
+	if !slices.Equal(before.Command, after.Command) {
+		changed("command", strings.Join(before.Command, " "), strings.Join(after.Command, " "))
+	}
+	if before.Domainname != after.Domainname {
+		changed("domainname", before.Domainname, after.Domainname)
+	}
+	if before.Networks != after.Networks {
+		changed("networks", before.Networks, after.Networks)
+	}
+	if before.Platform != after.Platform {
+		changed("platform", before.Platform, after.Platform)
+	}
+	if !proto.Equal(before.Healthcheck, after.Healthcheck) {
+		changes = append(changes, "healthcheck: changed")
+	}
+
+	// Catch all the other fields, like init and x-defang-static-files
+	if !proto.Equal(otherFields(before), otherFields(after)) {
+		changes = append(changes, "other settings changed")
+	}
+	return changes
+}
+
+// otherFields returns a copy of the service without the fields that are compared separately
+func otherFields(service *defangv1.Service) *defangv1.Service {
+	rest := proto.Clone(service).(*defangv1.Service)
+	rest.Image = ""
+	rest.Build = nil
+	rest.Environment = nil
+	rest.Secrets = nil
+	rest.Ports = nil
+	rest.Deploy = nil
+	rest.Command = nil
+	rest.Domainname = ""
+	rest.Networks = 0
+	rest.Internal = false // deprecated; same as networks
+	rest.Platform = 0
+	rest.Healthcheck = nil
+	rest.RegistryCredentials = "" // only filled in after the plan is confirmed; the image shows a change of registry
+	return rest
+}
+
+// sameBuildContext compares build contexts, which are either URLs or digests; the digest is typically sanitized in the URL
+func sameBuildContext(deployed, local string) bool {
+	if deployed == local {
+		return true
+	}
+	alphanumeric := func(s string) string {
+		return nonAlphanumeric.ReplaceAllLiteralString(s, "")
+	}
+	deployed, local = alphanumeric(deployed), alphanumeric(local)
+	return local != "" && strings.HasSuffix(deployed, local)
+}
+
+// diffKeys returns the added, changed, and removed keys; the values are not shown, because they might be sensitive
+func diffKeys(field string, before, after map[string]string) []string {
+	var changes []string
+	for key, value := range after {
+		if oldValue, ok := before[key]; !ok {
+			changes = append(changes, fmt.Sprintf("+ %s %s", field, key))
+		} else if oldValue != value {
+			changes = append(changes, fmt.Sprintf("~ %s %s", field, key))
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changes = append(changes, fmt.Sprintf("- %s %s", field, key))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i][2:] < changes[j][2:] })
+	return changes
+}
+
+func diffSets(field string, before, after []string) []string {
+	var changes []string
+	for _, name := range after {
+		if !slices.Contains(before, name) {
+			changes = append(changes, fmt.Sprintf("+ %s %s", field, name))
+		}
+	}
+	for _, name := range before {
+		if !slices.Contains(after, name) {
+			changes = append(changes, fmt.Sprintf("- %s %s", field, name))
+		}
+	}
+	return changes
+}
+
+func secretNames(secrets []*defangv1.Secret) []string {
+	names := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		names = append(names, secret.Source)
+	}
+	return names
+}
+
+func portNames(ports []*defangv1.Port) []string {
+	names := make([]string, 0, len(ports))
+	for _, port := range ports {
+		names = append(names, fmt.Sprintf("%d/%s (%s)", port.Target, strings.ToLower(port.Protocol.String()), strings.ToLower(port.Mode.String())))
+	}
+	return names
+}
+
+func printPlan(plan []ServiceDiff) {
+	var added, changed, removed, unchanged int
+	for _, diff := range plan {
+		switch diff.Action {
+		case DiffActionAdd:
+			added++
+			term.Println(termenv.ANSIBrightGreen, "+", diff.Name)
+		case DiffActionRemove:
+			removed++
+			term.Println(term.ErrorColor, "-", diff.Name)
+		case DiffActionChange:
+			changed++
+			term.Println(term.WarnColor, "~", diff.Name)
+			for _, change := range diff.Changes {
+				color := term.Color(termenv.ANSIWhite)
+				switch change[0] {
+				case '+':
+					color = termenv.ANSIBrightGreen
+				case '-':
+					color = term.ErrorColor
+				}
+				term.Println(color, "   ", change)
+			}
+		default:
+			unchanged++
+			term.Debug("  ", diff.Name, "(unchanged)")
+		}
+	}
+	term.Infof(" * Plan: %d to add, %d to change, %d to remove, %d unchanged", added, changed, removed, unchanged)
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+func TestDiffServices(t *testing.T) {
+	deployed := []*defangv1.Service{
+		{Name: "api", Image: "api:1", Environment: map[string]string{"A": "1", "B": "2"}, Ports: []*defangv1.Port{{Target: 80, Protocol: defangv1.Protocol_HTTP, Mode: defangv1.Mode_INGRESS}}},
+		{Name: "private", Image: "ghcr.io/org/private", RegistryCredentials: "arn:aws:secretsmanager:us-west-2:123456789012:secret:ghcr"},
+		{Name: "web", Build: &defangv1.Build{Context: "s3://bucket/uploads/sha256-abc_def_"}},
+		{Name: "worker", Image: "worker"},
+	}
+	services := []*defangv1.Service{
+		{Name: "api", Image: "api:2", Environment: map[string]string{"A": "1", "B": "3", "C": "4"}, Ports: []*defangv1.Port{{Target: 8080, Protocol: defangv1.Protocol_HTTP, Mode: defangv1.Mode_INGRESS}}},
+		{Name: "db", Image: "postgres"},
+		{Name: "private", Image: "ghcr.io/org/private"},
+		{Name: "web", Build: &defangv1.Build{Context: "sha256-abc+def="}},
+	}
+
+	plan := diffServices(deployed, services)
+	expected := []ServiceDiff{
+		{Name: "api", Action: DiffActionChange, Changes: []string{
+			"image: api:1 → api:2",
+			"~ environment B",
+			"+ environment C",
+			"+ port 8080/http (ingress)",
+			"- port 80/http (ingress)",
+		}},
+		{Name: "db", Action: DiffActionAdd},
+		{Name: "private", Action: DiffActionUnchanged},
+		{Name: "web", Action: DiffActionUnchanged},
+		{Name: "worker", Action: DiffActionRemove},
+	}
+	if len(plan) != len(expected) {
+		t.Fatalf("expected %d diffs, got %d: %v", len(expected), len(plan), plan)
+	}
+	for i, diff := range plan {
+		if diff.Name != expected[i].Name || diff.Action != expected[i].Action || !slices.Equal(diff.Changes, expected[i].Changes) {
+			t.Errorf("expected %v, got %v", expected[i], diff)
+		}
+	}
+}
+
+func TestSameBuildContext(t *testing.T) {
+	tests := []struct {
+		deployed, local string
+		want            bool
+	}{
+		{"https://bucket/uploads/sha256-abc_def_", "sha256-abc+def=", true},
+		{"https://bucket/uploads/sha256-abc_def_", "sha256-xyz+def=", false},
+		{"https://bucket/uploads/1234-5678", "", false},
+		{"/path/to/context", "/path/to/context", true},
+	}
+	for _, tt := range tests {
+		if got := sameBuildContext(tt.deployed, tt.local); got != tt.want {
+			t.Errorf("sameBuildContext(%q, %q) = %v, want %v", tt.deployed, tt.local, got, tt.want)
+		}
+	}
+}
@@ -15,7 +15,7 @@ import (
 	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
 )
 
-func convertServices(ctx context.Context, c client.Client, serviceConfigs compose.Services, force bool) ([]*defangv1.Service, error) {
+func convertServices(ctx context.Context, c client.Client, serviceConfigs compose.Services, upload UploadMode) ([]*defangv1.Service, error) {
 	// Create a regexp to detect private service names in environment variable values
 	var serviceNames []string
 	for _, svccfg := range serviceConfigs {
@@ -84,6 +84,7 @@ func convertServices(ctx context.Context, c client.Client, serviceConfigs compos
 						Devices: devices,
 					},
 				}
+				fixupFargateSize(svccfg.Name, deploy.Resources.Reservations)
 			}
 		}
 
@@ -91,7 +92,7 @@ func convertServices(ctx context.Context, c client.Client, serviceConfigs compos
 		var build *defangv1.Build
 		if svccfg.Build != nil {
 			// Pack the build context into a tarball and upload
-			url, err := getRemoteBuildContext(ctx, c, svccfg.Name, svccfg.Build, force)
+			url, err := getRemoteBuildContext(ctx, c, svccfg.Name, svccfg.Build, upload)
 			if err != nil {
 				return nil, err
 			}
@@ -175,6 +176,8 @@ func convertServices(ctx context.Context, c client.Client, serviceConfigs compos
 			staticFiles = staticFilesVal.(string) // already validated above
 		}
 
+		capacity, _ := getCapacity(svccfg) // already validated above
+
 		network := network(&svccfg)
 		ports := convertPorts(svccfg.Ports)
 		services = append(services, &defangv1.Service{
@@ -194,30 +197,61 @@ func convertServices(ctx context.Context, c client.Client, serviceConfigs compos
 			Platform:    convertPlatform(svccfg.Platform),
 			DnsRole:     dnsRole,
 			StaticFiles: staticFiles,
+			Capacity:    capacity,
 		})
 	}
 	return services, nil
 }
 
-// ComposeStart validates a compose project and uploads the services using the client
-func ComposeStart(ctx context.Context, c client.Client, force bool) (*defangv1.DeployResponse, error) {
+// loadServices loads and validates the compose project and converts its services
+func loadServices(ctx context.Context, c client.Client, upload UploadMode) (*compose.Project, []*defangv1.Service, error) {
 	project, err := c.LoadProject()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := validateProject(project); err != nil {
-		return nil, &ComposeError{err}
+		return nil, nil, &ComposeError{err}
 	}
 
-	services, err := convertServices(ctx, c, project.Services, force)
+	services, err := convertServices(ctx, c, project.Services, upload)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(services) == 0 {
-		return nil, &ComposeError{fmt.Errorf("no services found")}
+		return nil, nil, &ComposeError{fmt.Errorf("no services found")}
+	}
+	return project, services, nil
+}
+
+// ComposeConfig validates a compose project and shows the services, without looking at the deployed services
+func ComposeConfig(ctx context.Context, c client.Client) error {
+	_, services, err := loadServices(ctx, c, UploadModeDigest)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		PrintObject(service.Name, service)
 	}
+	return nil
+}
+
+// ComposeStart validates a compose project and uploads the services using the client; if confirm is true, it asks before deploying
+func ComposeStart(ctx context.Context, c client.Client, force, confirm bool) (*defangv1.DeployResponse, error) {
+	upload := UploadModeDigest
+	if force {
+		upload = UploadModeForce
+	}
+	if confirm {
+		upload = UploadModeIgnore // don't upload anything before the changes are confirmed
+	}
+	project, services, err := loadServices(ctx, c, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	printCostDelta(ctx, c, services) // also for --dry-run, to see the cost of a change before making it
 
 	if DoDryRun {
 		for _, service := range services {
@@ -226,6 +260,19 @@ func ComposeStart(ctx context.Context, c client.Client, force bool) (*defangv1.D
 		return nil, ErrDryRun
 	}
 
+	if confirm {
+		if err := confirmPlan(ctx, c, services); err != nil {
+			return nil, err
+		}
+		if err := uploadBuildContexts(ctx, c, project.Services, services, force); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := putRegistryCredentials(ctx, c, project, services); err != nil {
+		return nil, err
+	}
+
 	for _, service := range services {
 		term.Info(" * Deploying service", service.Name)
 	}
@@ -245,6 +292,30 @@ func ComposeStart(ctx context.Context, c client.Client, force bool) (*defangv1.D
 	return resp, nil
 }
 
+// uploadBuildContexts uploads the build contexts of services that were converted with UploadModeIgnore
+func uploadBuildContexts(ctx context.Context, c client.Client, serviceConfigs compose.Services, services []*defangv1.Service, force bool) error {
+	upload := UploadModeDigest
+	if force {
+		upload = UploadModeForce
+	}
+	for _, service := range services {
+		if service.Build == nil {
+			continue
+		}
+		for _, svccfg := range serviceConfigs {
+			if NormalizeServiceName(svccfg.Name) != service.Name {
+				continue
+			}
+			url, err := getRemoteBuildContext(ctx, c, svccfg.Name, svccfg.Build, upload)
+			if err != nil {
+				return err
+			}
+			service.Build.Context = url
+		}
+	}
+	return nil
+}
+
 func getResourceReservations(r compose.Resources) *compose.Resource {
 	if r.Reservations == nil {
 		// TODO: we might not want to default to all the limits, maybe only memory?
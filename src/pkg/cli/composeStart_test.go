@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/defang-io/defang/src/pkg/cli/client"
@@ -27,12 +28,39 @@ func TestComposeStart(t *testing.T) {
 	}))
 	defer server.Close()
 
-	_, err = ComposeStart(context.Background(), client.MockClient{UploadUrl: server.URL + "/", Project: proj}, false)
+	_, err = ComposeStart(context.Background(), client.MockClient{UploadUrl: server.URL + "/", Project: proj}, false, false)
 	if !errors.Is(err, ErrDryRun) {
 		t.Fatalf("ComposeStart() failed: %v", err)
 	}
 }
 
+func TestComposeConfig(t *testing.T) {
+	DoDryRun = true
+	defer func() { DoDryRun = false }()
+
+	loader := ComposeLoader{"../../tests/testproj/compose.yaml"}
+	proj, err := loader.LoadWithProjectName("tenant-id")
+	if err != nil {
+		t.Fatalf("LoadComposeWithProjectName() failed: %v", err)
+	}
+
+	c := noServicesClient{client.MockClient{Project: proj}, t}
+	if err := ComposeConfig(context.Background(), c); err != nil {
+		t.Fatalf("ComposeConfig() failed: %v", err)
+	}
+}
+
+// noServicesClient fails the test when the deployed services are requested
+type noServicesClient struct {
+	client.MockClient
+	t *testing.T
+}
+
+func (c noServicesClient) GetServices(context.Context) (*defangv1.ListServicesResponse, error) {
+	c.t.Error("unexpected call to GetServices")
+	return nil, errors.New("not logged in")
+}
+
 func TestComposeFixupEnv(t *testing.T) {
 	loader := ComposeLoader{"../../tests/fixupenv/compose.yaml"}
 	proj, err := loader.LoadWithProjectName("tenant-id")
@@ -40,7 +68,7 @@ func TestComposeFixupEnv(t *testing.T) {
 		t.Fatalf("LoadComposeWithProjectName() failed: %v", err)
 	}
 
-	services, err := convertServices(context.Background(), client.MockClient{}, proj.Services, false)
+	services, err := convertServices(context.Background(), client.MockClient{}, proj.Services, UploadModeDigest)
 	if err != nil {
 		t.Fatalf("convertServices() failed: %v", err)
 	}
@@ -73,3 +101,39 @@ func TestComposeFixupEnv(t *testing.T) {
 		t.Errorf("convertServices() failed: unable to find sensitive config variable %s", sensitiveKey)
 	}
 }
+
+func TestUploadBuildContexts(t *testing.T) {
+	loader := ComposeLoader{"../../tests/testproj/compose.yaml"}
+	proj, err := loader.LoadWithProjectName("tenant-id")
+	if err != nil {
+		t.Fatalf("LoadComposeWithProjectName() failed: %v", err)
+	}
+
+	var uploads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploads++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	c := client.MockClient{UploadUrl: server.URL + "/", Project: proj}
+
+	services, err := convertServices(context.Background(), c, proj.Services, UploadModeIgnore)
+	if err != nil {
+		t.Fatalf("convertServices() failed: %v", err)
+	}
+	if uploads != 0 {
+		t.Fatalf("expected no uploads before confirmation, got %d", uploads)
+	}
+
+	if err := uploadBuildContexts(context.Background(), c, proj.Services, services, false); err != nil {
+		t.Fatalf("uploadBuildContexts() failed: %v", err)
+	}
+	if uploads != 1 {
+		t.Errorf("expected 1 upload, got %d", uploads)
+	}
+	for _, service := range services {
+		if service.Build != nil && !strings.HasPrefix(service.Build.Context, server.URL) {
+			t.Errorf("expected build context of %q to be uploaded, got %q", service.Name, service.Build.Context)
+		}
+	}
+}
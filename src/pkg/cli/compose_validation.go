@@ -255,6 +255,14 @@ func validateProject(project *compose.Project) error {
 				return fmt.Errorf("x-defang-static-files must be a string")
 			}
 		}
+
+		if _, _, err := getRegistryCredentials(svccfg, nil); err != nil {
+			return err
+		}
+
+		if _, err := getCapacity(svccfg); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+func ListDeployments(ctx context.Context, client client.Client, limit int) error {
+	projectName, err := client.LoadProjectName()
+	if err != nil {
+		return err
+	}
+	term.Debug(" - Listing deployments of project", projectName)
+
+	// Get one more deployment to know what changed in the oldest one
+	fetch := 0 // as many as the provider allows
+	if limit > 0 {
+		fetch = limit + 1
+	}
+	deployments, err := client.ListDeployments(ctx, fetch)
+	if err != nil {
+		return err
+	}
+	if len(deployments) == 0 {
+		term.Info(" * No deployments found for project", projectName)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(term.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ETAG\tDEPLOYED\tBY\tCHANGES")
+	for i, deployment := range deployments {
+		if limit > 0 && i >= limit {
+			break
+		}
+		var previous *defangv1.ListServicesResponse
+		if i+1 < len(deployments) {
+			previous = deployments[i+1].Project
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", orDash(deployment.Etag), deployment.DeployedAt.Local().Format(time.DateTime), orDash(deployment.DeployedBy), orDash(summarizeChanges(previous, deployment.Project)))
+	}
+	return w.Flush()
+}
+
+// summarizeChanges returns a short summary of the services that changed between two deployments, like "+db ~api -worker"
+func summarizeChanges(previous, current *defangv1.ListServicesResponse) string {
+	var summary []string
+	for _, diff := range diffServices(projectServices(previous), projectServices(current)) {
+		if diff.Action != DiffActionUnchanged {
+			summary = append(summary, string(diff.Action)+diff.Name)
+		}
+	}
+	return strings.Join(summary, " ")
+}
+
+func projectServices(project *defangv1.ListServicesResponse) []*defangv1.Service {
+	services := make([]*defangv1.Service, 0, len(project.GetServices()))
+	for _, si := range project.GetServices() {
+		services = append(services, si.Service)
+	}
+	return services
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+func TestSummarizeChanges(t *testing.T) {
+	project := func(services ...*defangv1.Service) *defangv1.ListServicesResponse {
+		var serviceInfos []*defangv1.ServiceInfo
+		for _, service := range services {
+			serviceInfos = append(serviceInfos, &defangv1.ServiceInfo{Service: service})
+		}
+		return &defangv1.ListServicesResponse{Services: serviceInfos}
+	}
+
+	previous := project(&defangv1.Service{Name: "api", Image: "api:1"}, &defangv1.Service{Name: "web"}, &defangv1.Service{Name: "worker"})
+	current := project(&defangv1.Service{Name: "api", Image: "api:2"}, &defangv1.Service{Name: "db"}, &defangv1.Service{Name: "web"})
+
+	if got, want := summarizeChanges(previous, current), "~api +db -worker"; got != want {
+		t.Errorf("summarizeChanges() = %q, want %q", got, want)
+	}
+	if got, want := summarizeChanges(nil, previous), "+api +web +worker"; got != want {
+		t.Errorf("summarizeChanges() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/cli/client/byoc/aws"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+const hoursPerMonth = 730 // like the AWS pricing calculator
+
+// prices.json has the approximate on-demand prices in USD of the resources we deploy; it doesn't need to be exact,
+// but should be updated when AWS changes its prices.
+//
+//go:embed prices.json
+var pricesJson []byte
+
+type regionPrices struct {
+	FargateVCpuHour float64            `json:"fargate_vcpu_hour"`
+	FargateGBHour   float64            `json:"fargate_gb_hour"`
+	AlbHour         float64            `json:"alb_hour"`
+	AlbLcuHour      float64            `json:"alb_lcu_hour"`
+	NatGatewayHour  float64            `json:"nat_gateway_hour"`
+	GpuInstanceHour map[string]float64 `json:"gpu_instance_hour"`
+}
+
+type priceTable struct {
+	DefaultRegion       string                  `json:"default_region"`
+	FargateSpotDiscount float64                 `json:"fargate_spot_discount"`
+	FargateArmDiscount  float64                 `json:"fargate_arm_discount"`
+	Regions             map[string]regionPrices `json:"regions"`
+}
+
+var prices = func() (table priceTable) {
+	if err := json.Unmarshal(pricesJson, &table); err != nil {
+		panic(err) // embedded, so this is caught by the tests
+	}
+	return table
+}()
+
+// CostLine is the estimated monthly cost of a service or a shared resource, like the load balancer
+type CostLine struct {
+	Name        string
+	Description string
+	Monthly     float64
+}
+
+// CostEstimate is the estimated monthly cost of a set of services in a region
+type CostEstimate struct {
+	Region string
+	Lines  []CostLine
+	Total  float64
+}
+
+// ComposeEstimate prints the estimated monthly cost of the services in the Compose project
+func ComposeEstimate(ctx context.Context, c client.Client) (*CostEstimate, error) {
+	project, err := c.LoadProject()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateProject(project); err != nil {
+		return nil, &ComposeError{err}
+	}
+
+	// Only calculate the digests of the build contexts; there's no need to upload anything
+	services, err := convertServices(ctx, c, project.Services, UploadModeIgnore)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := estimateCost(services, pricingRegion())
+	if err != nil {
+		return nil, err
+	}
+	return estimate, printEstimate(term.Stdout, estimate)
+}
+
+// printCostDelta shows the estimated monthly cost of the services, compared to the ones that are currently deployed
+func printCostDelta(ctx context.Context, c client.Client, services []*defangv1.Service) {
+	region := pricingRegion()
+	estimate, err := estimateCost(services, region)
+	if err != nil {
+		term.Warn("Failed to estimate the cost:", err)
+		return
+	}
+	deployed, err := getDeployedServices(ctx, c)
+	if err != nil {
+		term.Warn("Failed to get the deployed services:", err)
+		return
+	}
+	current, err := estimateCost(deployed, region)
+	if err != nil {
+		term.Warn("Failed to estimate the cost of the deployed services:", err)
+		return
+	}
+	term.Infof(" * Estimated cost is $%.2f/month (%+.2f compared to the deployed services); run 'defang compose estimate' for details", estimate.Total, estimate.Total-current.Total)
+}
+
+// pricingRegion returns the region of the services, like the BYOC provider would; the playground runs in us-west-2
+func pricingRegion() string {
+	for _, region := range []string{aws.Region, aws.CdRegion, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")} {
+		if region != "" {
+			return region
+		}
+	}
+	return "us-west-2"
+}
+
+// estimateCost returns the estimated monthly cost of the services, including the shared load balancer and NAT gateway
+func estimateCost(services []*defangv1.Service, region string) (*CostEstimate, error) {
+	regionPrices, ok := prices.Regions[region]
+	if !ok {
+		term.Debugf(" - No prices for region %q; using those of %s", region, prices.DefaultRegion)
+		region = prices.DefaultRegion
+		regionPrices = prices.Regions[region]
+	}
+
+	estimate := &CostEstimate{Region: region}
+	var ingress, private bool
+	for _, service := range services {
+		line, err := estimateServiceCost(service, regionPrices)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", service.Name, err)
+		}
+		estimate.Lines = append(estimate.Lines, line)
+		for _, port := range service.Ports {
+			if port.Mode == defangv1.Mode_INGRESS {
+				ingress = true
+			}
+		}
+		if service.Networks == defangv1.Network_PRIVATE {
+			private = true
+		}
+	}
+	if ingress {
+		estimate.Lines = append(estimate.Lines, CostLine{
+			Name:        "(load balancer)",
+			Description: "ALB with 1 LCU",
+			Monthly:     (regionPrices.AlbHour + regionPrices.AlbLcuHour) * hoursPerMonth,
+		})
+	}
+	if private {
+		estimate.Lines = append(estimate.Lines, CostLine{
+			Name:        "(NAT gateway)",
+			Description: "excluding data processing",
+			Monthly:     regionPrices.NatGatewayHour * hoursPerMonth,
+		})
+	}
+	for _, line := range estimate.Lines {
+		estimate.Total += line.Monthly
+	}
+	return estimate, nil
+}
+
+func estimateServiceCost(service *defangv1.Service, regionPrices regionPrices) (CostLine, error) {
+	replicas := uint32(1)
+	if service.Deploy != nil {
+		replicas = service.Deploy.Replicas // can be 0
+	}
+	reservations := service.Deploy.GetResources().GetReservations()
+
+	var gpus uint32
+	for _, device := range reservations.GetDevices() {
+		gpus += max(device.Count, 1)
+	}
+	if gpus > 0 {
+		instance, err := ecs.GetGpuInstance(gpus)
+		if err != nil {
+			return CostLine{}, err
+		}
+		return CostLine{
+			Name:        service.Name,
+			Description: fmt.Sprintf("%d × %s", replicas, instance.Type),
+			Monthly:     float64(replicas) * regionPrices.GpuInstanceHour[instance.Type] * hoursPerMonth,
+		}, nil
+	}
+
+	cpus, memory := float64(reservations.GetCpus()), float64(reservations.GetMemory())
+	if cpus <= ecs.FargateMaxCpus && memory <= ecs.FargateMaxMemoryMiB {
+		cpu, mib := ecs.FixupFargateConfig(cpus, memory) // what we'd actually pay for
+		cpus, memory = float64(cpu)/1024, float64(mib)
+	}
+	hourly := cpus*regionPrices.FargateVCpuHour + memory/1024*regionPrices.FargateGBHour
+
+	details := []string{fmt.Sprintf("%d × %g vCPU, %g MiB", replicas, cpus, memory)}
+	if service.Platform == defangv1.Platform_LINUX_ARM64 {
+		hourly *= 1 - prices.FargateArmDiscount
+		details = append(details, "arm64")
+	}
+	if spot := spotShare(service.Capacity); spot > 0 {
+		hourly *= 1 - spot*prices.FargateSpotDiscount
+		details = append(details, fmt.Sprintf("%.0f%% spot", spot*100))
+	}
+	return CostLine{
+		Name:        service.Name,
+		Description: strings.Join(details, ", "),
+		Monthly:     float64(replicas) * hourly * hoursPerMonth,
+	}, nil
+}
+
+// spotShare returns the fraction of the tasks that run on Spot, from the weights of the capacity providers
+func spotShare(capacity *defangv1.Capacity) float64 {
+	total := capacity.GetSpot() + capacity.GetOnDemand()
+	if total == 0 {
+		return 0 // the default is on-demand
+	}
+	return float64(capacity.GetSpot()) / float64(total)
+}
+
+func printEstimate(w io.Writer, estimate *CostEstimate) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE\tRESOURCES\tMONTHLY")
+	for _, line := range estimate.Lines {
+		fmt.Fprintf(tw, "%s\t%s\t$%.2f\n", line.Name, line.Description, line.Monthly)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%s\t$%.2f\n", estimate.Region, estimate.Total)
+	return tw.Flush()
+}
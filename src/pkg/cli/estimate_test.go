@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+func TestPrices(t *testing.T) {
+	if _, ok := prices.Regions[prices.DefaultRegion]; !ok {
+		t.Fatalf("no prices for the default region %q", prices.DefaultRegion)
+	}
+	for region, p := range prices.Regions {
+		if p.FargateVCpuHour <= 0 || p.FargateGBHour <= 0 || p.AlbHour <= 0 || p.AlbLcuHour <= 0 || p.NatGatewayHour <= 0 {
+			t.Errorf("region %q is missing prices: %+v", region, p)
+		}
+		for _, instance := range gpuInstanceTypes {
+			if p.GpuInstanceHour[instance] <= 0 {
+				t.Errorf("region %q has no price for %s", region, instance)
+			}
+		}
+	}
+}
+
+var gpuInstanceTypes = []string{"g4dn.xlarge", "g4dn.12xlarge", "g4dn.metal"}
+
+func TestEstimateCost(t *testing.T) {
+	const small = (0.25*0.04048 + 0.5*0.004445) * hoursPerMonth // 0.25 vCPU, 512 MiB in us-east-1
+	const alb = (0.0225 + 0.008) * hoursPerMonth
+	const nat = 0.045 * hoursPerMonth
+
+	tests := []struct {
+		name      string
+		services  []*defangv1.Service
+		region    string
+		wantLines int
+		wantTotal float64
+	}{
+		{"empty", nil, "us-east-1", 0, 0},
+		{"default size", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC}}, "us-east-1", 1, small},
+		{"unknown region", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC}}, "mars-east-1", 1, small},
+		{"replicas", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC, Deploy: &defangv1.Deploy{Replicas: 3}}}, "us-east-1", 1, 3 * small},
+		{"zero replicas", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC, Deploy: &defangv1.Deploy{}}}, "us-east-1", 1, 0},
+		{"spot", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC, Capacity: &defangv1.Capacity{Spot: 1}}}, "us-east-1", 1, small * 0.3},
+		{"half spot", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC, Capacity: &defangv1.Capacity{Spot: 1, OnDemand: 1}}}, "us-east-1", 1, small * 0.65},
+		{"arm", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC, Platform: defangv1.Platform_LINUX_ARM64}}, "us-east-1", 1, small * 0.8},
+		{"ingress", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC, Ports: []*defangv1.Port{{Target: 80, Mode: defangv1.Mode_INGRESS}}}}, "us-east-1", 2, small + alb},
+		{"private", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PRIVATE}, {Name: "b", Networks: defangv1.Network_PRIVATE}}, "us-east-1", 3, 2*small + nat},
+		{"gpu", []*defangv1.Service{{Name: "a", Networks: defangv1.Network_PUBLIC, Deploy: &defangv1.Deploy{Replicas: 1, Resources: &defangv1.Resources{Reservations: &defangv1.Resource{Devices: []*defangv1.Device{{Count: 2}}}}}}}, "us-east-1", 1, 3.912 * hoursPerMonth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate, err := estimateCost(tt.services, tt.region)
+			if err != nil {
+				t.Fatalf("estimateCost() failed: %v", err)
+			}
+			if len(estimate.Lines) != tt.wantLines {
+				t.Errorf("estimateCost() has %d lines; want %d", len(estimate.Lines), tt.wantLines)
+			}
+			if math.Abs(estimate.Total-tt.wantTotal) > 0.001 {
+				t.Errorf("estimateCost() = %v; want %v", estimate.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestEstimateCostTooManyGpus(t *testing.T) {
+	services := []*defangv1.Service{{Name: "a", Deploy: &defangv1.Deploy{Replicas: 1, Resources: &defangv1.Resources{Reservations: &defangv1.Resource{Devices: []*defangv1.Device{{Count: 16}}}}}}}
+	if _, err := estimateCost(services, "us-east-1"); err == nil {
+		t.Error("estimateCost() should fail for 16 GPUs")
+	}
+}
+
+func TestPrintEstimate(t *testing.T) {
+	estimate := &CostEstimate{
+		Region: "us-east-1",
+		Lines:  []CostLine{{Name: "web", Description: "1 × 0.25 vCPU, 512 MiB", Monthly: 9.01}},
+		Total:  9.01,
+	}
+	var buf bytes.Buffer
+	if err := printEstimate(&buf, estimate); err != nil {
+		t.Fatal(err)
+	}
+	const want = `SERVICE  RESOURCES               MONTHLY
+web      1 × 0.25 vCPU, 512 MiB  $9.01
+TOTAL    us-east-1               $9.01
+`
+	if got := buf.String(); got != want {
+		t.Errorf("printEstimate() =\n%s\nwant\n%s", got, want)
+	}
+}
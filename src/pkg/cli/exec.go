@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+)
+
+func Exec(ctx context.Context, client client.Client, service string, args ...string) error {
+	service = NormalizeServiceName(service)
+	term.Debug(" - Executing", args, "in service", service)
+
+	if DoDryRun {
+		return ErrDryRun
+	}
+
+	return client.Exec(ctx, service, args...)
+}
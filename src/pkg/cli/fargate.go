@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+// fixupFargateSize rounds the reservations up to the nearest CPU and memory combination that Fargate supports, which
+// is what ECS would pick anyway; only the values that were set are changed, so the provider can still pick the others.
+func fixupFargateSize(name string, res *defangv1.Resource) {
+	if res == nil || len(res.Devices) > 0 || (res.Cpus == 0 && res.Memory == 0) {
+		return // GPU services don't run on Fargate
+	}
+	if res.Cpus > ecs.FargateMaxCpus || res.Memory > ecs.FargateMaxMemoryMiB {
+		return // too large for Fargate; this is reported by the quota checks
+	}
+	cpu, memory := ecs.FixupFargateConfig(float64(res.Cpus), float64(res.Memory))
+	cpus := float32(cpu) / 1024
+	if res.Cpus != 0 && res.Cpus != cpus {
+		if roundedCpu, _ := ecs.FixupFargateConfig(float64(res.Cpus), 0); cpus > float32(roundedCpu)/1024 {
+			warnf("service %q: memory %gMiB requires at least %g vCPU on Fargate; raising cpus from %g", name, res.Memory, cpus, res.Cpus)
+		} else {
+			warnf("service %q: cpus %g is not a valid Fargate size; using %g", name, res.Cpus, cpus)
+		}
+		res.Cpus = cpus
+	}
+	if res.Memory != 0 && res.Memory != float32(memory) {
+		warnf("service %q: memory %gMiB is not a valid Fargate size for %g vCPU; using %dMiB", name, res.Memory, cpus, memory)
+		res.Memory = float32(memory)
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+	"github.com/sirupsen/logrus"
+)
+
+func TestFixupFargateSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		res         *defangv1.Resource
+		wantCpus    float32
+		wantMemory  float32
+		wantWarning string
+	}{
+		{"valid", &defangv1.Resource{Cpus: 0.25, Memory: 512}, 0.25, 512, ""},
+		{"round up cpus", &defangv1.Resource{Cpus: 0.3}, 0.5, 0, "cpus 0.3 is not a valid Fargate size"},
+		{"round up memory", &defangv1.Resource{Memory: 700}, 0, 1024, "memory 700MiB is not a valid Fargate size"},
+		{"minimum memory", &defangv1.Resource{Cpus: 1, Memory: 1000}, 1, 2048, "memory 1000MiB is not a valid Fargate size"},
+		{"more cpus for memory", &defangv1.Resource{Cpus: 0.25, Memory: 4096}, 0.5, 4096, "memory 4096MiB requires at least 0.5 vCPU"},
+		{"too large", &defangv1.Resource{Cpus: 20, Memory: 1000}, 20, 1000, ""},
+		{"gpu", &defangv1.Resource{Cpus: 0.3, Devices: []*defangv1.Device{{Count: 1}}}, 0.3, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings bytes.Buffer
+			logrus.SetOutput(&warnings)
+
+			fixupFargateSize("test", tt.res)
+			if tt.res.Cpus != tt.wantCpus || tt.res.Memory != tt.wantMemory {
+				t.Errorf("fixupFargateSize() = %v, %v; want %v, %v", tt.res.Cpus, tt.res.Memory, tt.wantCpus, tt.wantMemory)
+			}
+			if tt.wantWarning == "" && warnings.Len() > 0 {
+				t.Errorf("unexpected warning: %s", warnings.String())
+			} else if !strings.Contains(warnings.String(), tt.wantWarning) {
+				t.Errorf("expected warning %q, got %q", tt.wantWarning, warnings.String())
+			}
+		})
+	}
+}
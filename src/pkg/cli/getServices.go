@@ -2,6 +2,12 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/defang-io/defang/src/pkg/cli/client"
 	"github.com/defang-io/defang/src/pkg/term"
@@ -29,3 +35,74 @@ func GetServices(ctx context.Context, client client.Client, long bool) error {
 	PrintObject("", serviceList)
 	return nil
 }
+
+// WatchServices prints the services and redraws them whenever their state changes, until the context is cancelled.
+func WatchServices(ctx context.Context, client client.Client) error {
+	projectName, err := client.LoadProjectName()
+	if err != nil {
+		return err
+	}
+	term.Debug(" - Watching services in project", projectName)
+
+	serviceList, err := client.GetServices(ctx)
+	if err != nil {
+		return err
+	}
+	services := make(map[string]*defangv1.ServiceInfo, len(serviceList.Services))
+	for _, si := range serviceList.Services {
+		services[si.Service.Name] = si
+	}
+
+	serverStream, err := client.Subscribe(ctx, &defangv1.SubscribeRequest{})
+	if err != nil {
+		return err
+	}
+	defer serverStream.Close()
+
+	printServiceTable(projectName, services)
+	for serverStream.Receive() {
+		for _, si := range serverStream.Msg().Services {
+			services[si.Service.GetName()] = si
+		}
+		printServiceTable(projectName, services)
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil // Ctrl+C
+	}
+	return serverStream.Err()
+}
+
+func printServiceTable(projectName string, services map[string]*defangv1.ServiceInfo) {
+	if term.IsTerminal {
+		term.Stdout.ClearScreen()
+	}
+	term.Info(" * Services in project", projectName, "as of", time.Now().Format(time.TimeOnly), "; press Ctrl+C to stop:")
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(term.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tSTATUS\tREPLICAS\tENDPOINTS")
+	for _, name := range names {
+		si := services[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, orDash(si.Status), formatReplicas(si), orDash(strings.Join(si.Endpoints, " ")))
+	}
+	w.Flush()
+}
+
+func formatReplicas(si *defangv1.ServiceInfo) string {
+	if si.DesiredReplicas == 0 && si.RunningReplicas == 0 {
+		return "-" // unknown
+	}
+	return fmt.Sprintf("%d/%d", si.RunningReplicas, si.DesiredReplicas)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
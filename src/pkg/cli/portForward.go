@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+)
+
+// ParsePortMapping parses a port mapping like "LOCAL:REMOTE" or "PORT", which uses the same port locally and remotely
+func ParsePortMapping(mapping string) (localPort, remotePort int, err error) {
+	local, remote, found := strings.Cut(mapping, ":")
+	if !found {
+		remote = local
+	}
+	if localPort, err = parsePort(local); err != nil {
+		return 0, 0, fmt.Errorf("invalid local port in %q: %w", mapping, err)
+	}
+	if remotePort, err = parsePort(remote); err != nil {
+		return 0, 0, fmt.Errorf("invalid remote port in %q: %w", mapping, err)
+	}
+	return localPort, remotePort, nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	if port == 0 {
+		return 0, errors.New("port must be between 1 and 65535")
+	}
+	return int(port), nil
+}
+
+func PortForward(ctx context.Context, client client.Client, service, mapping string) error {
+	localPort, remotePort, err := ParsePortMapping(mapping)
+	if err != nil {
+		return err
+	}
+
+	service = NormalizeServiceName(service)
+	term.Debug(" - Forwarding local port", localPort, "to port", remotePort, "of service", service)
+
+	if DoDryRun {
+		return ErrDryRun
+	}
+
+	term.Infof("Forwarding localhost:%d to %s:%d; press Ctrl+C to stop", localPort, service, remotePort)
+	return client.PortForward(ctx, service, localPort, remotePort)
+}
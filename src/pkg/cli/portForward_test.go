@@ -0,0 +1,33 @@
+package cli
+
+import "testing"
+
+func TestParsePortMapping(t *testing.T) {
+	tests := []struct {
+		mapping    string
+		localPort  int
+		remotePort int
+		wantErr    bool
+	}{
+		{"5432", 5432, 5432, false},
+		{"15432:5432", 15432, 5432, false},
+		{"8080:80", 8080, 80, false},
+		{"", 0, 0, true},
+		{"0:80", 0, 0, true},
+		{"80:", 0, 0, true},
+		{"65536:80", 0, 0, true},
+		{"http:80", 0, 0, true},
+		{"1:2:3", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mapping, func(t *testing.T) {
+			localPort, remotePort, err := ParsePortMapping(tt.mapping)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePortMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if localPort != tt.localPort || remotePort != tt.remotePort {
+				t.Errorf("ParsePortMapping() = %d:%d, want %d:%d", localPort, remotePort, tt.localPort, tt.remotePort)
+			}
+		})
+	}
+}
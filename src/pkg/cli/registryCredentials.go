@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	compose "github.com/compose-spec/compose-go/v2/types"
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/registry"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+// getRegistryCredentials returns either the ARN of an existing secret, or the credentials to store for the registry of the image.
+// The credentials come from x-defang-registry-credentials, or from the DOCKER_AUTH_CONFIG auths otherwise.
+func getRegistryCredentials(svccfg compose.ServiceConfig, auths map[string]registry.Credentials) (string, *registry.Credentials, error) {
+	switch val := svccfg.Extensions["x-defang-registry-credentials"].(type) {
+	case nil:
+		if svccfg.Build != nil {
+			return "", nil, nil // the image is pushed to our own registry
+		}
+		if creds, ok := auths[registry.Host(svccfg.Image)]; ok {
+			return "", &creds, nil
+		}
+		return "", nil, nil
+	case string:
+		if !strings.HasPrefix(val, "arn:aws:secretsmanager:") {
+			return "", nil, errors.New("x-defang-registry-credentials must be the ARN of a Secrets Manager secret, or have a username and password")
+		}
+		return val, nil, nil
+	case map[string]any:
+		username, _ := val["username"].(string)
+		password, _ := val["password"].(string)
+		if username == "" || password == "" || len(val) != 2 {
+			return "", nil, errors.New("x-defang-registry-credentials must have only a username and a password")
+		}
+		return "", &registry.Credentials{Username: username, Password: password}, nil
+	default:
+		return "", nil, errors.New("x-defang-registry-credentials must be a string or a mapping")
+	}
+}
+
+// putRegistryCredentials stores the credentials for private registries and sets the ARN of their secret on the services
+func putRegistryCredentials(ctx context.Context, c client.Client, project *compose.Project, services []*defangv1.Service) error {
+	auths, err := registry.ParseAuthConfig(os.Getenv("DOCKER_AUTH_CONFIG"))
+	if err != nil {
+		return err
+	}
+
+	arns := make(map[string]string)  // service name -> ARN
+	hosts := make(map[string]string) // service name -> registry host
+	creds := make(map[string]registry.Credentials)
+	for _, svccfg := range project.Services {
+		arn, cred, err := getRegistryCredentials(svccfg, auths)
+		if err != nil {
+			return &ComposeError{fmt.Errorf("service %q: %w", svccfg.Name, err)}
+		}
+		name := NormalizeServiceName(svccfg.Name)
+		if arn != "" {
+			arns[name] = arn
+		} else if cred != nil {
+			host := registry.Host(svccfg.Image)
+			if existing, ok := creds[host]; ok && existing != *cred {
+				return &ComposeError{fmt.Errorf("service %q: conflicting credentials for registry %q", svccfg.Name, host)}
+			}
+			creds[host] = *cred
+			hosts[name] = host
+		}
+	}
+
+	if len(creds) > 0 {
+		term.Debug(" - Storing credentials for", len(creds), "private registries")
+		secretArns, err := c.PutRegistryCredentials(ctx, creds)
+		if err != nil {
+			return err
+		}
+		for name, host := range hosts {
+			arns[name] = secretArns[host]
+		}
+	}
+
+	for _, service := range services {
+		service.RegistryCredentials = arns[service.Name]
+	}
+	return nil
+}
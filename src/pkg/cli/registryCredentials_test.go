@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"testing"
+
+	compose "github.com/compose-spec/compose-go/v2/types"
+	"github.com/defang-io/defang/src/pkg/registry"
+)
+
+func TestGetRegistryCredentials(t *testing.T) {
+	auths := map[string]registry.Credentials{"ghcr.io": {Username: "user", Password: "token"}}
+	tests := []struct {
+		name      string
+		svccfg    compose.ServiceConfig
+		wantArn   string
+		wantCreds *registry.Credentials
+		wantErr   bool
+	}{
+		{"public image", compose.ServiceConfig{Image: "alpine"}, "", nil, false},
+		{"auth config", compose.ServiceConfig{Image: "ghcr.io/org/app"}, "", &registry.Credentials{Username: "user", Password: "token"}, false},
+		{"build", compose.ServiceConfig{Image: "ghcr.io/org/app", Build: &compose.BuildConfig{Context: "."}}, "", nil, false},
+		{"arn", compose.ServiceConfig{Image: "quay.io/org/app", Extensions: map[string]any{
+			"x-defang-registry-credentials": "arn:aws:secretsmanager:us-west-2:123456789012:secret:quay",
+		}}, "arn:aws:secretsmanager:us-west-2:123456789012:secret:quay", nil, false},
+		{"mapping", compose.ServiceConfig{Image: "ghcr.io/org/app", Extensions: map[string]any{
+			"x-defang-registry-credentials": map[string]any{"username": "robot", "password": "secret"},
+		}}, "", &registry.Credentials{Username: "robot", Password: "secret"}, false},
+		{"missing password", compose.ServiceConfig{Image: "ghcr.io/org/app", Extensions: map[string]any{
+			"x-defang-registry-credentials": map[string]any{"username": "robot"},
+		}}, "", nil, true},
+		{"not an arn", compose.ServiceConfig{Image: "ghcr.io/org/app", Extensions: map[string]any{
+			"x-defang-registry-credentials": "robot:secret",
+		}}, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arn, creds, err := getRegistryCredentials(tt.svccfg, auths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if arn != tt.wantArn {
+				t.Errorf("expected ARN %q, got %q", tt.wantArn, arn)
+			}
+			if (creds == nil) != (tt.wantCreds == nil) || (creds != nil && *creds != *tt.wantCreds) {
+				t.Errorf("expected credentials %v, got %v", tt.wantCreds, creds)
+			}
+		})
+	}
+}
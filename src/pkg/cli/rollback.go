@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+// Rollback redeploys the services exactly as they were deployed with the given etag; the build contexts are unchanged, so nothing is rebuilt
+func Rollback(ctx context.Context, client client.Client, etag types.ETag) (types.ETag, error) {
+	term.Debug(" - Rolling back to deployment", etag)
+
+	deployment, err := client.GetDeployment(ctx, etag)
+	if err != nil {
+		return "", err
+	}
+	services := projectServices(deployment.Project)
+	if len(services) == 0 {
+		return "", fmt.Errorf("deployment %q has no services", etag)
+	}
+
+	if DoDryRun {
+		for _, service := range services {
+			PrintObject(service.Name, service)
+		}
+		return "", ErrDryRun
+	}
+
+	for _, service := range services {
+		term.Info(" * Deploying service", service.Name)
+	}
+	resp, err := client.Deploy(ctx, &defangv1.DeployRequest{Services: services})
+	if err != nil {
+		return "", err
+	}
+	return resp.Etag, nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+// Scale redeploys the project with a different number of replicas for the given service.
+func Scale(ctx context.Context, client client.Client, name string, replicas uint32) (types.ETag, error) {
+	name = NormalizeServiceName(name)
+	term.Debug(" - Scaling service", name, "to", replicas, "replicas")
+
+	serviceList, err := client.GetServices(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// Redeploy all services, because BYOC deploys the whole project at once
+	var found bool
+	services := make([]*defangv1.Service, 0, len(serviceList.Services))
+	for _, si := range serviceList.Services {
+		if si.Service.Name == name {
+			if si.Service.Deploy == nil {
+				si.Service.Deploy = &defangv1.Deploy{}
+			}
+			si.Service.Deploy.Replicas = replicas
+			found = true
+		}
+		services = append(services, si.Service)
+	}
+	if !found {
+		return "", fmt.Errorf("service %q not found", name)
+	}
+
+	if DoDryRun {
+		return "", ErrDryRun
+	}
+
+	resp, err := client.Deploy(ctx, &defangv1.DeployRequest{Services: services})
+	if err != nil {
+		return "", err
+	}
+	return resp.Etag, nil
+}
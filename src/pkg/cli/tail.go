@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bufbuild/connect-go"
@@ -33,6 +36,34 @@ var (
 
 type P = client.Property // shorthand for tracking properties
 
+// serviceColors is the palette used to give each service a stable color in the log prefix
+var serviceColors = []termenv.ANSIColor{
+	termenv.ANSIGreen,
+	termenv.ANSICyan,
+	termenv.ANSIBlue,
+	termenv.ANSIBrightGreen,
+	termenv.ANSIBrightCyan,
+	termenv.ANSIBrightBlue,
+	termenv.ANSIRed,
+	termenv.ANSIBrightYellow,
+}
+
+// serviceColor returns the color for the given service name; the same name always gets the same color.
+func serviceColor(service string) termenv.ANSIColor {
+	h := fnv.New32a()
+	h.Write([]byte(service))
+	return serviceColors[h.Sum32()%uint32(len(serviceColors))]
+}
+
+type TailOptions struct {
+	Services []string
+	Etag     string
+	Since    time.Time
+	Raw      bool
+	OnKey    func(key byte) // called for keys that are not handled by Tail itself
+	Output   sync.Locker    // held while printing, if others draw on the same terminal
+}
+
 // ParseTimeOrDuration parses a time string or duration string (e.g. 1h30m) and returns a time.Time.
 // At a minimum, this function supports RFC3339Nano, Go durations, and our own TimestampFormat (local).
 func ParseTimeOrDuration(str string) (time.Time, error) {
@@ -60,16 +91,16 @@ func ParseTimeOrDuration(str string) (time.Time, error) {
 }
 
 type CancelError struct {
-	Service string
-	Etag    string
-	Last    time.Time
+	Services []string
+	Etag     string
+	Last     time.Time
 	error
 }
 
 func (cerr *CancelError) Error() string {
 	cmd := "tail --since " + cerr.Last.UTC().Format(time.RFC3339Nano)
-	if cerr.Service != "" {
-		cmd += " --name " + cerr.Service
+	if len(cerr.Services) > 0 {
+		cmd += " --name " + strings.Join(cerr.Services, ",")
 	}
 	if cerr.Etag != "" {
 		cmd += " --etag " + cerr.Etag
@@ -84,9 +115,15 @@ func (cerr *CancelError) Unwrap() error {
 	return cerr.error
 }
 
-func Tail(ctx context.Context, client client.Client, service, etag string, since time.Time, raw bool) error {
-	if service != "" {
+func Tail(ctx context.Context, client client.Client, params TailOptions) error {
+	etag, since, raw := params.Etag, params.Since, params.Raw
+	var services []string
+	for _, service := range params.Services {
 		service = NormalizeServiceName(service)
+		if slices.Contains(services, service) {
+			continue
+		}
+		services = append(services, service)
 		// Show a warning if the service doesn't exist (yet);; TODO: could do fuzzy matching and suggest alternatives
 		if _, err := client.Get(ctx, &defangv1.ServiceID{Name: service}); err != nil {
 			switch connect.CodeOf(err) {
@@ -112,7 +149,7 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 
 	ctx, cancel := context.WithCancel(ctx)
 
-	serverStream, err := client.Tail(ctx, &defangv1.TailRequest{Service: service, Etag: etag, Since: timestamppb.New(since)})
+	serverStream, err := client.Tail(ctx, newTailRequest(services, etag, since))
 	if err != nil {
 		return err
 	}
@@ -121,13 +158,21 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 	spin := spinner.New()
 	doSpinner := !raw && term.CanColor && term.IsTerminal
 
+	lockOutput := func() (unlock func()) {
+		if params.Output == nil {
+			return func() {}
+		}
+		params.Output.Lock()
+		return params.Output.Unlock
+	}
+
 	if term.IsTerminal && !raw {
 		if doSpinner {
 			term.Stdout.HideCursor()
 			defer term.Stdout.ShowCursor()
 		}
 
-		if !DoVerbose {
+		if !DoVerbose || params.OnKey != nil {
 			term.Info(" * Press V to toggle verbose mode")
 			oldState, err := term.MakeUnbuf(int(os.Stdin.Fd()))
 			if err != nil {
@@ -147,7 +192,9 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 					case 3: // Ctrl-C
 						cancel()
 					case 10, 13: // Enter or Return
+						unlock := lockOutput()
 						fmt.Println(" ") // empty line, but overwrite the spinner
+						unlock()
 					case 'v', 'V':
 						verbose := !DoVerbose
 						DoVerbose = verbose
@@ -155,8 +202,14 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 						if verbose {
 							modeStr = "ON"
 						}
+						unlock := lockOutput()
 						term.Info(" * Verbose mode", modeStr)
+						unlock()
 						go client.Track("Verbose Toggled", P{"verbose", verbose})
+					default:
+						if params.OnKey != nil {
+							params.OnKey(b[0])
+						}
 					}
 				}
 			}()
@@ -167,7 +220,7 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 	for {
 		if !serverStream.Receive() {
 			if errors.Is(serverStream.Err(), context.Canceled) {
-				return &CancelError{Service: service, Etag: etag, Last: since, error: serverStream.Err()}
+				return &CancelError{Services: services, Etag: etag, Last: since, error: serverStream.Err()}
 			}
 
 			// TODO: detect ALB timeout (504) or Fabric restart and reconnect automatically
@@ -179,7 +232,7 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 					term.Fprint(term.Stderr, term.WarnColor, " ! Reconnecting...\r") // overwritten below
 				}
 				time.Sleep(time.Second)
-				serverStream, err = client.Tail(ctx, &defangv1.TailRequest{Service: service, Etag: etag, Since: timestamppb.New(since)})
+				serverStream, err = client.Tail(ctx, newTailRequest(services, etag, since))
 				if err != nil {
 					term.Debug(" - Reconnect failed:", err)
 					return err
@@ -194,6 +247,7 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 			return serverStream.Err() // returns nil on EOF
 		}
 		msg := serverStream.Msg()
+		unlock := lockOutput()
 
 		// Show a spinner if we're not in raw mode and have a TTY
 		if doSpinner {
@@ -245,8 +299,8 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 						l, _ := term.Print(termenv.ANSIYellow, msg.Etag, " ")
 						prefixLen += l
 					}
-					if service == "" {
-						l, _ := term.Print(termenv.ANSIGreen, msg.Service, " ")
+					if len(services) != 1 {
+						l, _ := term.Print(serviceColor(msg.Service), msg.Service, " ")
 						prefixLen += l
 					}
 					if DoVerbose {
@@ -267,7 +321,16 @@ func Tail(ctx context.Context, client client.Client, service, etag string, since
 				fmt.Println(line)
 			}
 		}
+		unlock()
+	}
+}
+
+func newTailRequest(services []string, etag string, since time.Time) *defangv1.TailRequest {
+	req := &defangv1.TailRequest{Services: services, Etag: etag, Since: timestamppb.New(since)}
+	if len(services) == 1 {
+		req.Service = services[0] // backwards compat with servers that don't support multiple services
 	}
+	return req
 }
 
 func isProgressDot(line string) bool {
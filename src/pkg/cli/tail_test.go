@@ -1,6 +1,9 @@
 package cli
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestIsProgressDot(t *testing.T) {
 	tests := []struct {
@@ -23,3 +26,27 @@ func TestIsProgressDot(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceColor(t *testing.T) {
+	if serviceColor("app") != serviceColor("app") {
+		t.Error("serviceColor() is not stable")
+	}
+	seen := make(map[string]bool)
+	for _, service := range []string{"app", "db", "redis", "worker", "web", "api"} {
+		seen[serviceColor(service).Sequence(false)] = true
+	}
+	if len(seen) < 2 {
+		t.Error("serviceColor() should use more than one color")
+	}
+}
+
+func TestNewTailRequest(t *testing.T) {
+	req := newTailRequest([]string{"app"}, "", time.Time{})
+	if req.Service != "app" {
+		t.Errorf("newTailRequest() Service = %q, want %q", req.Service, "app")
+	}
+	req = newTailRequest([]string{"app", "db"}, "etag", time.Time{})
+	if req.Service != "" || len(req.Services) != 2 || req.Etag != "etag" {
+		t.Errorf("newTailRequest() = %v", req)
+	}
+}
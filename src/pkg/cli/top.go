@@ -0,0 +1,392 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+	"github.com/muesli/termenv"
+	"github.com/pkg/browser"
+)
+
+const topRefreshInterval = 10 * time.Second
+
+// dashboard is the state of the services pane of the `top` command; the log pane is drawn by Tail.
+type dashboard struct {
+	cancel     context.CancelFunc
+	client     client.Client
+	ctx        context.Context
+	escape     int        // state of the escape sequence for the arrow keys
+	mu         sync.Mutex // protects the state below
+	output     sync.Mutex // serializes writing to the terminal with the log pane
+	paneHeight int
+	project    string
+	redraw     chan struct{}
+	selected   int
+	services   map[string]*defangv1.ServiceInfo
+	stats      map[string]client.ServiceStats
+	status     string // result of the last action
+	width      int
+}
+
+// Top shows a full-screen dashboard with the services of the project above their logs, until the user quits.
+func Top(ctx context.Context, client client.Client, since time.Time) error {
+	if !term.IsTerminal {
+		return errors.New("top requires a terminal")
+	}
+
+	projectName, err := client.LoadProjectName()
+	if err != nil {
+		return err
+	}
+	term.Debug(" - Showing dashboard for project", projectName)
+
+	if DoDryRun {
+		return ErrDryRun
+	}
+
+	width, height, err := term.GetSize()
+	if err != nil {
+		return err
+	}
+
+	serviceList, err := client.GetServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	d := &dashboard{
+		cancel:   cancel,
+		client:   client,
+		ctx:      ctx,
+		project:  projectName,
+		redraw:   make(chan struct{}, 1),
+		services: make(map[string]*defangv1.ServiceInfo, len(serviceList.Services)),
+		width:    width,
+	}
+	for _, si := range serviceList.Services {
+		d.services[si.Service.Name] = si
+	}
+	// Title, header, services, help and status lines, but leave at least half the screen for the logs
+	d.paneHeight = min(len(d.services)+4, height/2)
+
+	// The logs from Tail scroll in the region below the services pane
+	term.Stdout.AltScreen()
+	term.Stdout.ClearScreen()
+	term.Stdout.ChangeScrollingRegion(d.paneHeight+1, height)
+	defer func() {
+		term.Stdout.ChangeScrollingRegion(1, height)
+		term.Stdout.ExitAltScreen()
+	}()
+	term.Stdout.MoveCursor(d.paneHeight+1, 1)
+	d.requestDraw()
+
+	go d.drawLoop()
+	go d.subscribe()
+	go d.refresh()
+
+	err = Tail(ctx, client, TailOptions{Since: since, OnKey: d.onKey, Output: &d.output})
+	var cerr *CancelError
+	if errors.As(err, &cerr) || errors.Is(err, context.Canceled) {
+		return nil // user quit
+	}
+	return err
+}
+
+// subscribe updates the services pane with the service state changes
+func (d *dashboard) subscribe() {
+	serverStream, err := d.client.Subscribe(d.ctx, &defangv1.SubscribeRequest{})
+	if err != nil {
+		term.Debug(" - Failed to subscribe:", err)
+		return
+	}
+	defer serverStream.Close()
+
+	for serverStream.Receive() {
+		d.mu.Lock()
+		for _, si := range serverStream.Msg().Services {
+			d.services[si.Service.GetName()] = si
+		}
+		d.mu.Unlock()
+		d.requestDraw()
+	}
+	term.Debug(" - Subscription ended:", serverStream.Err())
+}
+
+// refresh periodically updates the resource usage and the list of services
+func (d *dashboard) refresh() {
+	ticker := time.NewTicker(topRefreshInterval)
+	defer ticker.Stop()
+	for {
+		stats, err := d.client.ServiceStats(d.ctx)
+		if err != nil {
+			term.Debug(" - Failed to get service stats:", err)
+		}
+
+		serviceList, err := d.client.GetServices(d.ctx)
+		if err != nil {
+			term.Debug(" - Failed to get services:", err)
+		}
+
+		d.mu.Lock()
+		d.stats = stats
+		if serviceList != nil {
+			// Only add or remove services; the state updates come from the subscription
+			current := make(map[string]bool, len(serviceList.Services))
+			for _, si := range serviceList.Services {
+				current[si.Service.Name] = true
+				if _, ok := d.services[si.Service.Name]; !ok {
+					d.services[si.Service.Name] = si
+				}
+			}
+			for name := range d.services {
+				if !current[name] {
+					delete(d.services, name)
+				}
+			}
+		}
+		d.mu.Unlock()
+		d.requestDraw()
+
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *dashboard) sortedNames() []string {
+	names := make([]string, 0, len(d.services))
+	for name := range d.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *dashboard) selectedService() *defangv1.ServiceInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	names := d.sortedNames()
+	if len(names) == 0 {
+		return nil
+	}
+	return d.services[names[min(d.selected, len(names)-1)]]
+}
+
+func (d *dashboard) setStatus(status string) {
+	d.mu.Lock()
+	d.status = status
+	d.mu.Unlock()
+	d.requestDraw()
+}
+
+// requestDraw schedules a redraw of the services pane; multiple requests are coalesced
+func (d *dashboard) requestDraw() {
+	select {
+	case d.redraw <- struct{}{}:
+	default: // already scheduled
+	}
+}
+
+// drawLoop is the only goroutine that draws the services pane, so the escape sequences are not interleaved
+func (d *dashboard) drawLoop() {
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-d.redraw:
+			d.draw()
+		}
+	}
+}
+
+func (d *dashboard) draw() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.output.Lock() // don't move the cursor while Tail is printing
+	defer d.output.Unlock()
+
+	names := d.sortedNames()
+	d.selected = max(0, min(d.selected, len(names)-1))
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tSTATUS\tREPLICAS\tCPU\tMEMORY\tENDPOINTS")
+	for _, name := range names {
+		si := d.services[name]
+		replicas, cpu, memory := formatReplicas(si), "-", "-"
+		if stats, ok := d.stats[name]; ok {
+			replicas = fmt.Sprintf("%d/%d", stats.RunningReplicas, stats.DesiredReplicas)
+			if stats.CPUReserved > 0 {
+				cpu = fmt.Sprintf("%.0f%% of %g", 100*stats.CPUUtilized/stats.CPUReserved, stats.CPUReserved)
+			}
+			if stats.MemoryReserved > 0 {
+				memory = fmt.Sprintf("%.0f/%.0f MiB", stats.MemoryUtilized, stats.MemoryReserved)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", name, orDash(si.Status), replicas, cpu, memory, orDash(strings.Join(si.Endpoints, " ")))
+	}
+	w.Flush()
+	rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	// Scroll the services if they don't fit in the pane
+	visible := max(1, d.paneHeight-4)
+	offset := max(0, d.selected-visible+1)
+
+	term.Stdout.SaveCursorPosition()
+	defer term.Stdout.RestoreCursorPosition()
+	line := 1
+	printLine := func(color term.Color, s string, reverse bool) {
+		term.Stdout.MoveCursor(line, 1)
+		term.Stdout.ClearLine()
+		if runes := []rune(s); len(runes) > d.width {
+			s = string(runes[:d.width])
+		}
+		style := term.Stdout.String(s)
+		if term.CanColor {
+			style = style.Foreground(color)
+		}
+		if reverse {
+			style = style.Reverse()
+		}
+		fmt.Fprint(term.Stdout, style)
+		line++
+	}
+	printLine(term.InfoColor, fmt.Sprintf(" * Project %s as of %s", d.project, time.Now().Format(time.TimeOnly)), false)
+	printLine(term.BrightCyan, rows[0], false)
+	for i := offset; i < len(names) && i < offset+visible; i++ {
+		printLine(termenv.ANSIWhite, rows[i+1], i == d.selected)
+	}
+	for line < d.paneHeight-1 {
+		printLine(termenv.ANSIWhite, "", false)
+	}
+	printLine(term.DebugColor, "↑/↓ select  R restart  +/- scale  O open endpoint  V verbose  Q quit", false)
+	printLine(term.WarnColor, d.status, false)
+}
+
+func (d *dashboard) onKey(key byte) {
+	// Arrow keys are sent as escape sequences, like ESC [ A
+	switch {
+	case d.escape == 0 && key == 27:
+		d.escape = 1
+		return
+	case d.escape == 1 && key == '[':
+		d.escape = 2
+		return
+	case d.escape == 2:
+		d.escape = 0
+		switch key {
+		case 'A':
+			key = 'k'
+		case 'B':
+			key = 'j'
+		default:
+			return
+		}
+	default:
+		d.escape = 0
+	}
+
+	switch key {
+	case 'j', 'J':
+		d.mu.Lock()
+		d.selected++
+		d.mu.Unlock()
+		d.requestDraw()
+	case 'k', 'K':
+		d.mu.Lock()
+		d.selected--
+		d.mu.Unlock()
+		d.requestDraw()
+	case 'q', 'Q':
+		d.cancel()
+	case 'r', 'R':
+		go d.restart()
+	case '+', '=':
+		go d.scale(1)
+	case '-', '_':
+		go d.scale(-1)
+	case 'o', 'O':
+		d.open()
+	}
+}
+
+func (d *dashboard) restart() {
+	si := d.selectedService()
+	if si == nil {
+		return
+	}
+	name := si.Service.Name
+	d.setStatus("Restarting " + name + "...")
+	etag, err := Restart(d.ctx, d.client, name)
+	if err != nil {
+		d.setStatus(fmt.Sprintf("Failed to restart %s: %v", name, err))
+		return
+	}
+	d.setStatus(fmt.Sprintf("Restarted %s with deployment ID %s", name, etag))
+}
+
+func (d *dashboard) scale(delta int) {
+	si := d.selectedService()
+	if si == nil {
+		return
+	}
+	name := si.Service.Name
+	replicas := 1 // Compose default
+	if si.Service.Deploy != nil && si.Service.Deploy.Replicas > 0 {
+		replicas = int(si.Service.Deploy.Replicas)
+	}
+	replicas += delta
+	if replicas < 0 {
+		return
+	}
+	d.setStatus(fmt.Sprintf("Scaling %s to %d replicas...", name, replicas))
+	etag, err := Scale(d.ctx, d.client, name, uint32(replicas))
+	if err != nil {
+		d.setStatus(fmt.Sprintf("Failed to scale %s: %v", name, err))
+		return
+	}
+	d.setStatus(fmt.Sprintf("Scaling %s to %d replicas with deployment ID %s", name, replicas, etag))
+}
+
+func (d *dashboard) open() {
+	si := d.selectedService()
+	if si == nil {
+		return
+	}
+	url := publicURL(si)
+	if url == "" {
+		d.setStatus("Service " + si.Service.Name + " has no public endpoint")
+		return
+	}
+	if err := browser.OpenURL(url); err != nil {
+		d.setStatus(fmt.Sprintf("Failed to open %s: %v", url, err))
+	}
+}
+
+// publicURL returns the URL of the first public endpoint of the service, or "" if it has none
+func publicURL(si *defangv1.ServiceInfo) string {
+	if si.Service.Domainname != "" {
+		return "https://" + si.Service.Domainname
+	}
+	for i, endpoint := range si.Endpoints {
+		if i < len(si.Service.Ports) && si.Service.Ports[i].Mode == defangv1.Mode_INGRESS {
+			return "https://" + endpoint
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"testing"
+
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+func TestPublicURL(t *testing.T) {
+	ingress := &defangv1.Port{Mode: defangv1.Mode_INGRESS, Target: 80}
+	host := &defangv1.Port{Mode: defangv1.Mode_HOST, Target: 5432}
+	tests := []struct {
+		name string
+		si   *defangv1.ServiceInfo
+		want string
+	}{
+		{"no ports", &defangv1.ServiceInfo{Service: &defangv1.Service{Name: "worker"}}, ""},
+		{"host port", &defangv1.ServiceInfo{Service: &defangv1.Service{Name: "db", Ports: []*defangv1.Port{host}}, Endpoints: []string{"db.internal:5432"}}, ""},
+		{"ingress port", &defangv1.ServiceInfo{Service: &defangv1.Service{Name: "app", Ports: []*defangv1.Port{host, ingress}}, Endpoints: []string{"app.internal:5432", "app--80.example.com"}}, "https://app--80.example.com"},
+		{"domainname", &defangv1.ServiceInfo{Service: &defangv1.Service{Name: "app", Domainname: "example.org", Ports: []*defangv1.Port{ingress}}, Endpoints: []string{"app--80.example.com"}}, "https://example.org"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := publicURL(tt.si); got != tt.want {
+				t.Errorf("publicURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestDrawCoalesces(t *testing.T) {
+	d := &dashboard{redraw: make(chan struct{}, 1)}
+	for i := 0; i < 3; i++ {
+		d.requestDraw() // must not block while a redraw is pending
+	}
+	if len(d.redraw) != 1 {
+		t.Errorf("expected one pending redraw, got %d", len(d.redraw))
+	}
+}
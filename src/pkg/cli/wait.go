@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+const ServiceStateDeploymentCompleted = "DEPLOYMENT_COMPLETED"
+
+type ErrDeploymentFailed struct {
+	Service string
+	State   string
+}
+
+func (e ErrDeploymentFailed) Error() string {
+	return fmt.Sprintf("service %q failed to deploy: %s", e.Service, e.State)
+}
+
+func isFailedState(state string) bool {
+	return strings.HasSuffix(state, "_FAILED") // BUILD_FAILED, DEPLOYMENT_FAILED, etc.
+}
+
+// WaitServiceState waits until all the given services reach the target state, or returns an error as soon as one fails.
+func WaitServiceState(ctx context.Context, client client.Client, targetState string, etag types.ETag, services []string) error {
+	if len(services) == 0 {
+		return errors.New("no services to wait for")
+	}
+
+	term.Debug(" - Waiting for services", services, "to reach state", targetState)
+
+	if DoDryRun {
+		return ErrDryRun
+	}
+
+	// Keep track of the last known state of each service that hasn't reached the target state yet
+	pending := make(map[string]string, len(services))
+	for _, service := range services {
+		pending[NormalizeServiceName(service)] = ""
+	}
+
+	serverStream, err := client.Subscribe(ctx, &defangv1.SubscribeRequest{Etag: etag})
+	if err != nil {
+		return err
+	}
+	defer serverStream.Close()
+
+	for len(pending) > 0 {
+		if !serverStream.Receive() {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out waiting for %s", pendingStates(pending))
+			}
+			if err := serverStream.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("stream closed while waiting for %s", pendingStates(pending))
+		}
+
+		for _, si := range serverStream.Msg().Services {
+			if etag != "" && si.Etag != etag {
+				continue // update for a different deployment
+			}
+			name := si.Service.GetName()
+			state, ok := pending[name]
+			if !ok {
+				continue
+			}
+			if state != si.Status {
+				term.Info(" * Service", name, "is in state", si.Status)
+			}
+			switch {
+			case si.Status == targetState:
+				delete(pending, name)
+			case isFailedState(si.Status):
+				return ErrDeploymentFailed{Service: name, State: si.Status}
+			default:
+				pending[name] = si.Status
+			}
+		}
+	}
+	return nil
+}
+
+func pendingStates(pending map[string]string) string {
+	var states []string
+	for name, state := range pending {
+		if state == "" {
+			state = "unknown state"
+		}
+		states = append(states, fmt.Sprintf("%s (%s)", name, state))
+	}
+	sort.Strings(states)
+	return strings.Join(states, ", ")
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/defang-io/defang/src/pkg/cli/client"
+	defangv1 "github.com/defang-io/defang/src/protos/io/defang/v1"
+)
+
+type mockSubscribeStream struct {
+	responses []*defangv1.SubscribeResponse
+	response  *defangv1.SubscribeResponse
+}
+
+func (m *mockSubscribeStream) Close() error { return nil }
+func (m *mockSubscribeStream) Err() error   { return nil }
+func (m *mockSubscribeStream) Msg() *defangv1.SubscribeResponse {
+	return m.response
+}
+func (m *mockSubscribeStream) Receive() bool {
+	if len(m.responses) == 0 {
+		return false
+	}
+	m.response, m.responses = m.responses[0], m.responses[1:]
+	return true
+}
+
+type mockSubscribeClient struct {
+	client.MockClient
+	responses []*defangv1.SubscribeResponse
+}
+
+func (m mockSubscribeClient) Subscribe(context.Context, *defangv1.SubscribeRequest) (client.ServerStream[defangv1.SubscribeResponse], error) {
+	return &mockSubscribeStream{responses: m.responses}, nil
+}
+
+func serviceInfo(name, etag, status string) *defangv1.ServiceInfo {
+	return &defangv1.ServiceInfo{Service: &defangv1.Service{Name: name}, Etag: etag, Status: status}
+}
+
+func TestWaitServiceState(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []*defangv1.SubscribeResponse
+		wantErr   error
+	}{
+		{
+			name: "all completed",
+			responses: []*defangv1.SubscribeResponse{
+				{Services: []*defangv1.ServiceInfo{serviceInfo("app", "a", "BUILD_RUNNING")}},
+				{Services: []*defangv1.ServiceInfo{serviceInfo("app", "a", ServiceStateDeploymentCompleted), serviceInfo("db", "a", ServiceStateDeploymentCompleted)}},
+			},
+		},
+		{
+			name: "build failed",
+			responses: []*defangv1.SubscribeResponse{
+				{Services: []*defangv1.ServiceInfo{serviceInfo("app", "a", ServiceStateDeploymentCompleted), serviceInfo("db", "a", "BUILD_FAILED")}},
+			},
+			wantErr: ErrDeploymentFailed{Service: "db", State: "BUILD_FAILED"},
+		},
+		{
+			name: "other deployment ignored",
+			responses: []*defangv1.SubscribeResponse{
+				{Services: []*defangv1.ServiceInfo{serviceInfo("app", "a", ServiceStateDeploymentCompleted), serviceInfo("db", "b", "DEPLOYMENT_FAILED")}},
+				{Services: []*defangv1.ServiceInfo{serviceInfo("db", "a", ServiceStateDeploymentCompleted)}},
+			},
+		},
+		{
+			name: "stream closed",
+			responses: []*defangv1.SubscribeResponse{
+				{Services: []*defangv1.ServiceInfo{serviceInfo("app", "a", ServiceStateDeploymentCompleted)}},
+			},
+			wantErr: errors.New("stream closed while waiting for db (unknown state)"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := mockSubscribeClient{responses: tt.responses}
+			err := WaitServiceState(context.Background(), client, ServiceStateDeploymentCompleted, "a", []string{"app", "db"})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("WaitServiceState() error = %v, want nil", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Errorf("WaitServiceState() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,16 +3,34 @@ package aws
 import (
 	"context"
 	"errors"
+	"os"
 	"os/exec"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type Region string
 
+var (
+	// RoleARN is the role that all AWS clients assume, for example to deploy from a tooling account into a workload account
+	RoleARN = os.Getenv("DEFANG_AWS_ROLE_ARN")
+	// ExternalID is passed when assuming RoleARN, if the role's trust policy requires it
+	ExternalID = os.Getenv("DEFANG_AWS_EXTERNAL_ID")
+
+	assumeRoleMutex    sync.Mutex
+	assumeRoleProvider aws.CredentialsProvider // cached, so the role is only assumed once per session
+	assumeRoleKey      string
+
+	invalidSessionNameChars = regexp.MustCompile(`[^\w+=,.@-]+`)
+)
+
 type Aws struct {
 	Region Region
 }
@@ -53,9 +71,43 @@ func LoadDefaultConfig(ctx context.Context, region Region) (aws.Config, error) {
 		cliProvider,
 		cfg.Credentials,
 	)
+
+	if RoleARN != "" {
+		cfg.Credentials = getAssumeRoleProvider(cfg)
+	}
 	return cfg, nil
 }
 
+func getAssumeRoleProvider(cfg aws.Config) aws.CredentialsProvider {
+	assumeRoleMutex.Lock()
+	defer assumeRoleMutex.Unlock()
+
+	key := RoleARN + "|" + ExternalID
+	if assumeRoleProvider == nil || assumeRoleKey != key {
+		// The STS client uses the ambient credentials from cfg
+		assumeRoleProvider = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if ExternalID != "" {
+				o.ExternalID = aws.String(ExternalID)
+			}
+			o.RoleSessionName = RoleSessionName()
+		}))
+		assumeRoleKey = key
+	}
+	return assumeRoleProvider
+}
+
+// RoleSessionName returns the session name for assumed roles, which shows up in CloudTrail as the identity of the user
+func RoleSessionName() string {
+	name := "defang"
+	if user := invalidSessionNameChars.ReplaceAllLiteralString(os.Getenv("USER"), "-"); user != "" {
+		name += "-" + user
+	}
+	if len(name) > 64 {
+		name = name[:64] // the maximum length of a role session name
+	}
+	return name
+}
+
 func GetAccountID(arn string) string {
 	parts := strings.Split(arn, ":")
 	return parts[4]
@@ -0,0 +1,27 @@
+package aws
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRoleSessionName(t *testing.T) {
+	tests := []struct {
+		user string
+		want string
+	}{
+		{"", "defang"},
+		{"alice", "defang-alice"},
+		{"alice@example.com", "defang-alice@example.com"},
+		{"DOMAIN\\bob smith", "defang-DOMAIN-bob-smith"},
+		{strings.Repeat("x", 100), "defang-" + strings.Repeat("x", 57)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			t.Setenv("USER", tt.user)
+			if got := RoleSessionName(); got != tt.want {
+				t.Errorf("RoleSessionName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
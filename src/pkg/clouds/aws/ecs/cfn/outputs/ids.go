@@ -1,10 +1,22 @@
 package outputs
 
 const (
-	BucketName      = "bucketName"
-	ClusterName     = "clusterName"
-	LogGroupARN     = "logGroupArn"
-	SecurityGroupID = "securityGroupId"
-	SubnetID        = "subnetId"
-	TaskDefArn      = "taskDefArn"
+	BucketName          = "bucketName"
+	ClusterName         = "clusterName"
+	EgressCIDRs         = "egressCidrs"
+	GpuCapacityProvider = "gpuCapacityProvider"
+	LogDestinationARN   = "logDestinationArn"
+	LogDestinationRole  = "logDestinationRoleArn"
+	LogGroupARN         = "logGroupArn"
+	LogRetentionDays    = "logRetentionDays"
+	PermissionsBoundary = "permissionsBoundaryArn"
+	PrivateSubnet       = "privateSubnet"
+	SecretsKeyARN       = "secretsKeyArn"
+	SecurityGroupID     = "securityGroupId"
+	Spot                = "spot"
+	SubnetID            = "subnetId"
+	SubnetIDs           = "subnetIds"
+	TaskDefArn          = "taskDefArn"
+	TaskRoleARN         = "taskRoleArn"
+	VpcID               = "vpcId"
 )
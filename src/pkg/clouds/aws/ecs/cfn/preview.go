@@ -0,0 +1,255 @@
+package cfn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfnTypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
+)
+
+// StackChange is a change to a resource of the stack, as reported by a change set
+type StackChange struct {
+	Action       string // Add, Modify, Remove, ...
+	LogicalID    string
+	ResourceType string
+	Replacement  string // True, False, or Conditional; only for Modify
+}
+
+// StackDrift is a resource of the stack that was changed outside of CloudFormation
+type StackDrift struct {
+	LogicalID    string
+	ResourceType string
+	Status       string   // MODIFIED or DELETED
+	Properties   []string // paths of the properties that differ
+}
+
+// ChangeSet is a change set for setting up the stack; it's kept until it's executed or discarded, so what's applied is what was previewed
+type ChangeSet struct {
+	ID      string // empty if there are no changes
+	Changes []StackChange
+}
+
+// PreviewSetUp creates a change set with the changes that SetUp would make to the stack, without making them
+func (a *AwsEcs) PreviewSetUp(ctx context.Context, containers []types.Container) (*ChangeSet, error) {
+	template, err := a.templateBody(ctx, containers)
+	if err != nil {
+		return nil, err
+	}
+
+	cfn, err := a.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSetType := cfnTypes.ChangeSetTypeUpdate
+	if _, err := cfn.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: &a.stackName}); err != nil {
+		if !isStackNotFound(err) {
+			return nil, err
+		}
+		changeSetType = cfnTypes.ChangeSetTypeCreate
+	}
+
+	cco, err := cfn.CreateChangeSet(ctx, &cloudformation.CreateChangeSetInput{
+		StackName:     ptr.String(a.stackName),
+		ChangeSetName: ptr.String(fmt.Sprintf("setup-%d", time.Now().Unix())),
+		ChangeSetType: changeSetType,
+		TemplateBody:  ptr.String(string(template)),
+		Capabilities:  []cfnTypes.Capability{cfnTypes.CapabilityCapabilityNamedIam},
+		Parameters:    registryParameters(a.Overrides.RegistryCredentials),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet, err := a.describeChangeSet(ctx, *cco.Id)
+	if changeSet == nil || err != nil {
+		// Don't leave behind a change set that can't be executed; use a new context in case ctx was canceled
+		if err := a.DiscardChangeSet(context.Background(), *cco.Id); err != nil {
+			term.Debug(" - Failed to discard change set", *cco.Id+":", err)
+		}
+	}
+	if changeSet == nil && err == nil {
+		changeSet = &ChangeSet{} // no changes
+	}
+	return changeSet, err
+}
+
+// describeChangeSet waits for the change set to be created and returns its changes, or nil if it has no changes
+func (a *AwsEcs) describeChangeSet(ctx context.Context, changeSetID string) (*ChangeSet, error) {
+	cfn, err := a.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	term.Debug(" - Waiting for change set", changeSetID, "to be created")
+	err = cloudformation.NewChangeSetCreateCompleteWaiter(cfn, func(o *cloudformation.ChangeSetCreateCompleteWaiterOptions) {
+		o.MinDelay = 1
+	}).Wait(ctx, &cloudformation.DescribeChangeSetInput{ChangeSetName: &changeSetID}, stackTimeout)
+
+	changeSet := &ChangeSet{ID: changeSetID}
+	input := &cloudformation.DescribeChangeSetInput{ChangeSetName: &changeSetID}
+	for {
+		dcso, derr := cfn.DescribeChangeSet(ctx, input)
+		if derr != nil {
+			return nil, derr
+		}
+		if dcso.Status == cfnTypes.ChangeSetStatusFailed {
+			// A change set without changes fails, which is not an error for a preview
+			if dcso.StatusReason != nil && strings.Contains(*dcso.StatusReason, "didn't contain changes") {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to create change set: %s", ptr.ToString(dcso.StatusReason))
+		}
+		if err != nil {
+			return nil, err // the waiter timed out
+		}
+		for _, change := range dcso.Changes {
+			if rc := change.ResourceChange; rc != nil {
+				changeSet.Changes = append(changeSet.Changes, StackChange{
+					Action:       string(rc.Action),
+					LogicalID:    ptr.ToString(rc.LogicalResourceId),
+					ResourceType: ptr.ToString(rc.ResourceType),
+					Replacement:  string(rc.Replacement),
+				})
+			}
+		}
+		if dcso.NextToken == nil {
+			return changeSet, nil
+		}
+		input.NextToken = dcso.NextToken
+	}
+}
+
+// ExecuteChangeSet applies a change set from PreviewSetUp and waits for the stack to be created or updated
+func (a *AwsEcs) ExecuteChangeSet(ctx context.Context, changeSetID string) error {
+	cfn, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A stack that is being reviewed was created by the change set, so executing it creates the stack
+	dcso, err := cfn.DescribeChangeSet(ctx, &cloudformation.DescribeChangeSetInput{ChangeSetName: &changeSetID})
+	if err != nil {
+		return err
+	}
+	dso, err := cfn.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: dcso.StackId})
+	if err != nil {
+		return err
+	}
+	create := len(dso.Stacks) > 0 && dso.Stacks[0].StackStatus == cfnTypes.StackStatusReviewInProgress
+
+	if _, err := cfn.ExecuteChangeSet(ctx, &cloudformation.ExecuteChangeSetInput{ChangeSetName: &changeSetID}); err != nil {
+		return err
+	}
+
+	if create {
+		fmt.Println("Waiting for stack", a.stackName, "to be created...") // TODO: verbose only
+		dso, err = cloudformation.NewStackCreateCompleteWaiter(cfn, create1s).WaitForOutput(ctx, &cloudformation.DescribeStacksInput{
+			StackName: dcso.StackId,
+		}, stackTimeout)
+	} else {
+		fmt.Println("Waiting for stack", a.stackName, "to be updated...") // TODO: verbose only
+		dso, err = cloudformation.NewStackUpdateCompleteWaiter(cfn, update1s).WaitForOutput(ctx, &cloudformation.DescribeStacksInput{
+			StackName: dcso.StackId,
+		}, stackTimeout)
+	}
+	if err != nil {
+		return err
+	}
+	return a.fillWithOutputs(dso)
+}
+
+// DiscardChangeSet deletes a change set from PreviewSetUp, and the empty stack it created if the stack didn't exist yet
+func (a *AwsEcs) DiscardChangeSet(ctx context.Context, changeSetID string) error {
+	cfn, err := a.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	dcso, err := cfn.DescribeChangeSet(ctx, &cloudformation.DescribeChangeSetInput{ChangeSetName: &changeSetID})
+	if err != nil {
+		return err
+	}
+	if _, err := cfn.DeleteChangeSet(ctx, &cloudformation.DeleteChangeSetInput{ChangeSetName: &changeSetID}); err != nil {
+		return err
+	}
+	dso, err := cfn.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: dcso.StackId})
+	if err != nil {
+		return err
+	}
+	if len(dso.Stacks) > 0 && dso.Stacks[0].StackStatus == cfnTypes.StackStatusReviewInProgress {
+		_, err = cfn.DeleteStack(ctx, &cloudformation.DeleteStackInput{StackName: dcso.StackId})
+	}
+	return err
+}
+
+// DetectDrift runs drift detection on the stack and returns the resources that were changed outside of CloudFormation
+func (a *AwsEcs) DetectDrift(ctx context.Context) ([]StackDrift, error) {
+	cfn, err := a.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dsdo, err := cfn.DetectStackDrift(ctx, &cloudformation.DetectStackDriftInput{
+		StackName: ptr.String(a.stackName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	term.Debug(" - Waiting for drift detection", *dsdo.StackDriftDetectionId, "to complete")
+	for {
+		status, err := cfn.DescribeStackDriftDetectionStatus(ctx, &cloudformation.DescribeStackDriftDetectionStatusInput{
+			StackDriftDetectionId: dsdo.StackDriftDetectionId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if status.DetectionStatus == cfnTypes.StackDriftDetectionStatusDetectionFailed {
+			// Drift detection fails for resources that don't support it, but the other resources are still checked
+			term.Debug(" - Drift detection failed:", ptr.ToString(status.DetectionStatusReason))
+		}
+		if status.DetectionStatus != cfnTypes.StackDriftDetectionStatusDetectionInProgress {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	var drifts []StackDrift
+	paginator := cloudformation.NewDescribeStackResourceDriftsPaginator(cfn, &cloudformation.DescribeStackResourceDriftsInput{
+		StackName: ptr.String(a.stackName),
+		StackResourceDriftStatusFilters: []cfnTypes.StackResourceDriftStatus{
+			cfnTypes.StackResourceDriftStatusModified,
+			cfnTypes.StackResourceDriftStatusDeleted,
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, drift := range page.StackResourceDrifts {
+			var properties []string
+			for _, diff := range drift.PropertyDifferences {
+				properties = append(properties, ptr.ToString(diff.PropertyPath))
+			}
+			drifts = append(drifts, StackDrift{
+				LogicalID:    ptr.ToString(drift.LogicalResourceId),
+				ResourceType: ptr.ToString(drift.ResourceType),
+				Status:       string(drift.StackResourceDriftStatus),
+				Properties:   properties,
+			})
+		}
+	}
+	return drifts, nil
+}
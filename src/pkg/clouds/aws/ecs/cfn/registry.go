@@ -0,0 +1,155 @@
+package cfn
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfnTypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/smithy-go/ptr"
+	goformation "github.com/awslabs/goformation/v7/cloudformation"
+	"github.com/awslabs/goformation/v7/cloudformation/secretsmanager"
+	"github.com/awslabs/goformation/v7/cloudformation/tags"
+	awsecs "github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/registry"
+	"github.com/defang-io/defang/src/pkg/term"
+)
+
+// registryHosts returns the registry hosts in a stable order, so the parameters don't change between updates
+func registryHosts(creds map[string]registry.Credentials) []string {
+	hosts := make([]string, 0, len(creds))
+	for host := range creds {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// registryLogicalName turns a registry host into the alphanumeric part of a logical ID, like "ghcr.io" into "GhcrIo",
+// so adding or removing a registry doesn't change the logical IDs of the secrets of the other registries
+func registryLogicalName(host string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range host {
+		switch {
+		case 'a' <= r && r <= 'z':
+			if upper {
+				r -= 'a' - 'A'
+			}
+			fallthrough
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			sb.WriteRune(r)
+			upper = false
+		default:
+			upper = true // separators like '.', '-', and ':' are dropped
+		}
+	}
+	return sb.String()
+}
+
+func registryParameterID(host string) string {
+	return "RegistryCredentials" + registryLogicalName(host)
+}
+
+func registrySecretID(host string) string {
+	return "RegistrySecret" + registryLogicalName(host)
+}
+
+// addRegistrySecrets adds a Secrets Manager secret for each registry and returns their logical IDs by registry host.
+// The credentials are passed as NoEcho parameters, so unlike the Docker Hub secret, they don't end up in the template.
+func addRegistrySecrets(template *goformation.Template, defaultTags []tags.Tag, creds map[string]registry.Credentials) map[string]string {
+	secretIDs := make(map[string]string, len(creds))
+	for _, host := range registryHosts(creds) {
+		template.Parameters[registryParameterID(host)] = goformation.Parameter{
+			Type:        "String",
+			Description: ptr.String("Credentials for " + host),
+			NoEcho:      ptr.Bool(true),
+		}
+		// This is $0.40 per secret per month, so only for registries that are actually used
+		template.Resources[registrySecretID(host)] = &secretsmanager.Secret{
+			Tags:         defaultTags,
+			Description:  ptr.String("Credentials for the private registry " + host),
+			SecretString: goformation.RefPtr(registryParameterID(host)),
+		}
+		secretIDs[host] = registrySecretID(host)
+	}
+	return secretIDs
+}
+
+// registryParameters returns the values for the parameters that were added by addRegistrySecrets
+func registryParameters(creds map[string]registry.Credentials) []cfnTypes.Parameter {
+	var params []cfnTypes.Parameter
+	for _, host := range registryHosts(creds) {
+		value, err := json.Marshal(creds[host])
+		if err != nil {
+			panic(err) // there's no reason this should ever fail
+		}
+		params = append(params, cfnTypes.Parameter{
+			ParameterKey:   ptr.String(registryParameterID(host)),
+			ParameterValue: ptr.String(string(value)),
+		})
+	}
+	return params
+}
+
+func createRegistryTemplate(creds map[string]registry.Credentials) *goformation.Template {
+	template := goformation.NewTemplate()
+	defaultTags := []tags.Tag{
+		{
+			Key:   "CreatedBy",
+			Value: awsecs.ProjectName,
+		},
+	}
+	for host, secretID := range addRegistrySecrets(template, defaultTags, creds) {
+		template.Outputs[secretID] = goformation.Output{
+			Value:       goformation.Ref(secretID),
+			Description: ptr.String("ARN of the secret with the credentials for " + host),
+		}
+	}
+	return template
+}
+
+// SetUpRegistrySecrets creates or updates a stack with only the secrets for Overrides.RegistryCredentials and returns their ARNs by registry host
+func (a *AwsEcs) SetUpRegistrySecrets(ctx context.Context) (map[string]string, error) {
+	template, err := createRegistryTemplate(a.Overrides.RegistryCredentials).YAML()
+	if err != nil {
+		return nil, err
+	}
+
+	// Upsert
+	if err := a.updateStackAndWait(ctx, string(template)); err != nil {
+		if !isStackNotFound(err) {
+			return nil, err
+		}
+		if err := a.createStackAndWait(ctx, string(template)); err != nil {
+			return nil, err
+		}
+	}
+
+	cfn, err := a.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dso, err := cfn.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: &a.stackName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	arns := make(map[string]string)
+	for _, host := range registryHosts(a.Overrides.RegistryCredentials) {
+		for _, stack := range dso.Stacks {
+			for _, output := range stack.Outputs {
+				if *output.OutputKey == registrySecretID(host) {
+					arns[host] = *output.OutputValue
+				}
+			}
+		}
+		if arns[host] == "" {
+			term.Debug(" - Missing output", registrySecretID(host), "in stack", a.stackName)
+		}
+	}
+	return arns, nil
+}
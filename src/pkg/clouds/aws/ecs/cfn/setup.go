@@ -15,12 +15,17 @@ import (
 	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
 	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn/outputs"
 	"github.com/defang-io/defang/src/pkg/clouds/aws/region"
+	"github.com/defang-io/defang/src/pkg/registry"
+	"github.com/defang-io/defang/src/pkg/term"
 	"github.com/defang-io/defang/src/pkg/types"
 )
 
 type AwsEcs struct {
 	ecs.AwsEcs
-	stackName string
+	Overrides     TemplateOverrides
+	SecretsKeyARN string   // only if Overrides.CreateSecretsKey is set
+	SubnetIDs     []string // other existing subnets, like those of the services; only with VpcID
+	stackName     string
 }
 
 // var _ types.Driver = (*AwsEcs)(nil)
@@ -36,6 +41,41 @@ func OptionVPCAndSubnetID(ctx context.Context, vpcID, subnetID string) func(type
 	}
 }
 
+// OptionSecurityGroup sets the CIDRs that can connect to the container ports and the CIDRs the task can connect to
+func OptionSecurityGroup(ingressCIDRs, egressCIDRs []string) func(types.Driver) error {
+	return func(d types.Driver) error {
+		if ecs, ok := d.(*AwsEcs); ok {
+			ecs.Overrides.IngressCIDRs = ingressCIDRs
+			ecs.Overrides.EgressCIDRs = egressCIDRs
+			return nil
+		}
+		if len(ingressCIDRs) > 0 || len(egressCIDRs) > 0 {
+			return errors.New("only AwsEcs driver supports ingress and egress CIDRs")
+		}
+		return nil
+	}
+}
+
+// OptionRegistryCredentials sets the credentials for pulling images from private registries
+func OptionRegistryCredentials(creds map[string]registry.Credentials) func(types.Driver) error {
+	return func(d types.Driver) error {
+		if ecs, ok := d.(*AwsEcs); ok {
+			ecs.Overrides.RegistryCredentials = creds
+		}
+		return nil // the docker driver uses the credentials of the docker daemon
+	}
+}
+
+// OptionSpot sets whether tasks run on Fargate Spot (the default) or on on-demand Fargate
+func OptionSpot(spot bool) func(types.Driver) error {
+	return func(d types.Driver) error {
+		if ecs, ok := d.(*AwsEcs); ok {
+			ecs.Spot = spot
+		}
+		return nil // the docker driver runs locally
+	}
+}
+
 func New(stack string, region region.Region) *AwsEcs {
 	if stack == "" {
 		panic("stack must be set")
@@ -73,6 +113,7 @@ func (a *AwsEcs) updateStackAndWait(ctx context.Context, templateBody string) er
 		StackName:    ptr.String(a.stackName),
 		TemplateBody: ptr.String(templateBody),
 		Capabilities: []cfnTypes.Capability{cfnTypes.CapabilityCapabilityNamedIam},
+		Parameters:   registryParameters(a.Overrides.RegistryCredentials),
 	})
 	if err != nil {
 		// Go SDK doesn't have --no-fail-on-empty-changeset; ignore ValidationError: No updates are to be performed.
@@ -110,6 +151,7 @@ func (a *AwsEcs) createStackAndWait(ctx context.Context, templateBody string) er
 		TemplateBody: ptr.String(templateBody),
 		Capabilities: []cfnTypes.Capability{cfnTypes.CapabilityCapabilityNamedIam},
 		OnFailure:    cfnTypes.OnFailureDelete,
+		Parameters:   registryParameters(a.Overrides.RegistryCredentials),
 	})
 	if err != nil {
 		// Ignore AlreadyExistsException; return all other errors
@@ -129,8 +171,37 @@ func (a *AwsEcs) createStackAndWait(ctx context.Context, templateBody string) er
 	return a.fillWithOutputs(dso)
 }
 
+// templateBody returns the template of the stack for the given containers
+func (a *AwsEcs) templateBody(ctx context.Context, containers []types.Container) ([]byte, error) {
+	var gpus uint32
+	for _, container := range containers {
+		gpus += container.Gpus
+	}
+	if gpus > 0 {
+		if _, err := ecs.GetGpuInstance(gpus); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.VpcID == "" {
+		// Keep using the existing VPC of the stack, if any, so the VPC options are only needed for the first setup
+		if err := a.fillVpcOutputs(ctx); err != nil {
+			term.Debug(" - Failed to get the VPC of stack", a.stackName+":", err)
+		}
+	}
+
+	overrides := a.Overrides
+	overrides.VpcID = a.VpcID
+	if a.VpcID != "" {
+		overrides.SubnetID = a.SubNetID
+		overrides.PrivateSubnet = a.PrivateSubnet
+		overrides.SubnetIDs = a.SubnetIDs
+	}
+	return createTemplate(a.stackName, containers, overrides, a.Spot).YAML()
+}
+
 func (a *AwsEcs) SetUp(ctx context.Context, containers []types.Container) error {
-	template, err := createTemplate(a.stackName, containers, TemplateOverrides{VpcID: a.VpcID}, a.Spot).YAML()
+	template, err := a.templateBody(ctx, containers)
 	if err != nil {
 		return err
 	}
@@ -138,8 +209,7 @@ func (a *AwsEcs) SetUp(ctx context.Context, containers []types.Container) error
 	// Upsert
 	if err := a.updateStackAndWait(ctx, string(template)); err != nil {
 		// Check if the stack doesn't exist; if so, create it, otherwise return the error
-		var apiError smithy.APIError
-		if ok := errors.As(err, &apiError); !ok || (apiError.ErrorCode() != "ValidationError") || !strings.HasSuffix(apiError.ErrorMessage(), "does not exist") {
+		if !isStackNotFound(err) {
 			return err
 		}
 		return a.createStackAndWait(ctx, string(template))
@@ -147,6 +217,37 @@ func (a *AwsEcs) SetUp(ctx context.Context, containers []types.Container) error
 	return nil
 }
 
+// CreateOrFill creates the stack if it doesn't exist yet, and otherwise only fills the outputs of the existing stack,
+// so an existing stack is only changed by SetUp; it returns whether SetUp would change the template of the existing stack
+func (a *AwsEcs) CreateOrFill(ctx context.Context, containers []types.Container) (bool, error) {
+	template, err := a.templateBody(ctx, containers)
+	if err != nil {
+		return false, err
+	}
+
+	cfn, err := a.newClient(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	gto, err := cfn.GetTemplate(ctx, &cloudformation.GetTemplateInput{
+		StackName: ptr.String(a.stackName),
+	})
+	if err != nil {
+		if !isStackNotFound(err) {
+			return false, err
+		}
+		return false, a.createStackAndWait(ctx, string(template))
+	}
+	return ptr.ToString(gto.TemplateBody) != string(template), a.FillOutputs(ctx)
+}
+
+// isStackNotFound returns whether the error is the ValidationError for a stack that doesn't exist
+func isStackNotFound(err error) bool {
+	var apiError smithy.APIError
+	return errors.As(err, &apiError) && apiError.ErrorCode() == "ValidationError" && strings.HasSuffix(apiError.ErrorMessage(), "does not exist")
+}
+
 func (a *AwsEcs) FillOutputs(ctx context.Context) error {
 	// println("Filling outputs for stack", stackId)
 	cfn, err := a.newClient(ctx)
@@ -164,35 +265,93 @@ func (a *AwsEcs) FillOutputs(ctx context.Context) error {
 	return a.fillWithOutputs(dso)
 }
 
+// StackOutputs returns the outputs of the existing stack, or nil if the stack doesn't exist yet
+func (a *AwsEcs) StackOutputs(ctx context.Context) (map[string]string, error) {
+	cfn, err := a.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dso, err := cfn.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: &a.stackName,
+	})
+	if err != nil {
+		if isStackNotFound(err) {
+			return nil, nil // new stack
+		}
+		return nil, err
+	}
+	stackOutputs := make(map[string]string)
+	for _, stack := range dso.Stacks {
+		for _, output := range stack.Outputs {
+			stackOutputs[*output.OutputKey] = *output.OutputValue
+		}
+	}
+	return stackOutputs, nil
+}
+
+// fillVpcOutputs fills the VPC outputs of an existing stack; the other outputs might change when the stack is updated
+func (a *AwsEcs) fillVpcOutputs(ctx context.Context) error {
+	stackOutputs, err := a.StackOutputs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range []string{outputs.PrivateSubnet, outputs.SubnetID, outputs.SubnetIDs, outputs.VpcID} {
+		if value, ok := stackOutputs[key]; ok {
+			a.fillWithOutput(key, value)
+		}
+	}
+	return nil
+}
+
 func (a *AwsEcs) fillWithOutputs(dso *cloudformation.DescribeStacksOutput) error {
+	a.GpuCapacityProvider = "" // only if the latest template has GPUs
 	for _, stack := range dso.Stacks {
 		for _, output := range stack.Outputs {
-			switch *output.OutputKey {
-			case outputs.SubnetID:
-				if a.SubNetID == "" {
-					a.SubNetID = *output.OutputValue
-				}
-			case outputs.TaskDefArn:
-				if a.TaskDefARN == "" {
-					a.TaskDefARN = *output.OutputValue
-				}
-			case outputs.ClusterName:
-				a.ClusterName = *output.OutputValue
-			case outputs.LogGroupARN:
-				a.LogGroupARN = *output.OutputValue
-			case outputs.SecurityGroupID:
-				a.SecurityGroupID = *output.OutputValue
-			case outputs.BucketName:
-				a.BucketName = *output.OutputValue
-				// default:; TODO: should do this but only for stack the driver created
-				// 	return fmt.Errorf("unknown output key %q", *output.OutputKey)
-			}
+			a.fillWithOutput(*output.OutputKey, *output.OutputValue)
 		}
 	}
 
 	return nil
 }
 
+func (a *AwsEcs) fillWithOutput(key, value string) {
+	switch key {
+	case outputs.SubnetID:
+		if a.SubNetID == "" {
+			a.SubNetID = value
+		}
+	case outputs.SubnetIDs:
+		if len(a.SubnetIDs) == 0 {
+			a.SubnetIDs = strings.Split(value, ",")
+		}
+	case outputs.TaskDefArn:
+		if a.TaskDefARN == "" {
+			a.TaskDefARN = value
+		}
+	case outputs.ClusterName:
+		a.ClusterName = value
+	case outputs.GpuCapacityProvider:
+		a.GpuCapacityProvider = value
+	case outputs.LogGroupARN:
+		a.LogGroupARN = value
+	case outputs.PrivateSubnet:
+		a.PrivateSubnet = value == "true"
+	case outputs.SecretsKeyARN:
+		a.SecretsKeyARN = value
+	case outputs.SecurityGroupID:
+		a.SecurityGroupID = value
+	case outputs.BucketName:
+		a.BucketName = value
+	case outputs.VpcID:
+		if a.VpcID == "" {
+			a.VpcID = value
+		}
+		// default:; TODO: should do this but only for stack the driver created
+		// 	return fmt.Errorf("unknown output key %q", key)
+	}
+}
+
 func (a *AwsEcs) Run(ctx context.Context, env map[string]string, cmd ...string) (ecs.TaskArn, error) {
 	if err := a.FillOutputs(ctx); err != nil {
 		return nil, err
@@ -201,6 +360,21 @@ func (a *AwsEcs) Run(ctx context.Context, env map[string]string, cmd ...string)
 	return a.AwsEcs.Run(ctx, env, cmd...)
 }
 
+func (a *AwsEcs) RunWithTags(ctx context.Context, env map[string]string, tags map[string]string, cmd ...string) (ecs.TaskArn, error) {
+	if err := a.FillOutputs(ctx); err != nil {
+		return nil, err
+	}
+
+	return a.AwsEcs.RunWithTags(ctx, env, tags, cmd...)
+}
+
+func (a *AwsEcs) ListTasks(ctx context.Context) ([]ecs.TaskInfo, error) {
+	if err := a.FillOutputs(ctx); err != nil {
+		return nil, err
+	}
+	return a.AwsEcs.ListTasks(ctx)
+}
+
 func (a *AwsEcs) Tail(ctx context.Context, taskArn ecs.TaskArn) error {
 	if err := a.FillOutputs(ctx); err != nil {
 		return err
@@ -242,6 +416,29 @@ func (a *AwsEcs) TearDown(ctx context.Context) error {
 	}, stackTimeout)
 }
 
+// ListStacks returns the names of the existing stacks in the region that start with the given prefix
+func ListStacks(ctx context.Context, region region.Region, prefix string) ([]string, error) {
+	cfg, err := common.LoadDefaultConfig(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	paginator := cloudformation.NewListStacksPaginator(cloudformation.NewFromConfig(cfg), &cloudformation.ListStacksInput{})
+	for paginator.HasMorePages() {
+		lso, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, stack := range lso.StackSummaries {
+			if stack.StackStatus != cfnTypes.StackStatusDeleteComplete && strings.HasPrefix(ptr.ToString(stack.StackName), prefix) {
+				names = append(names, *stack.StackName)
+			}
+		}
+	}
+	return names, nil
+}
+
 // delete1s is a functional option for cloudformation.StackDeleteCompleteWaiter that sets the MinDelay to 1
 func delete1s(o *cloudformation.StackDeleteCompleteWaiterOptions) {
 	o.MinDelay = 1
@@ -6,15 +6,19 @@ import (
 	"encoding/json"
 	"math"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/aws/smithy-go/ptr"
 	"github.com/awslabs/goformation/v7/cloudformation"
+	"github.com/awslabs/goformation/v7/cloudformation/autoscaling"
 	"github.com/awslabs/goformation/v7/cloudformation/ec2"
 	"github.com/awslabs/goformation/v7/cloudformation/ecr"
 	"github.com/awslabs/goformation/v7/cloudformation/ecs"
 	"github.com/awslabs/goformation/v7/cloudformation/iam"
+	"github.com/awslabs/goformation/v7/cloudformation/kms"
 	"github.com/awslabs/goformation/v7/cloudformation/logs"
 	"github.com/awslabs/goformation/v7/cloudformation/policies"
 	"github.com/awslabs/goformation/v7/cloudformation/s3"
@@ -22,16 +26,16 @@ import (
 	"github.com/awslabs/goformation/v7/cloudformation/tags"
 	awsecs "github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
 	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn/outputs"
+	"github.com/defang-io/defang/src/pkg/registry"
 	"github.com/defang-io/defang/src/pkg/types"
 )
 
 const (
-	createVpcResources   = true // TODO: make this configurable, add an option to use the default VPC
-	maxCachePrefixLength = 20   // prefix must be 2-20 characters long; should be 30 https://github.com/hashicorp/terraform-provider-aws/pull/34716
+	maxCachePrefixLength = 20 // prefix must be 2-20 characters long; should be 30 https://github.com/hashicorp/terraform-provider-aws/pull/34716
 )
 
 var (
-	dockerHubUsername    = os.Getenv("DOCKERHUB_USERNAME") // TODO: support DOCKER_AUTH_CONFIG
+	dockerHubUsername    = os.Getenv("DOCKERHUB_USERNAME") // for the pull-through cache; see TemplateOverrides.RegistryCredentials otherwise
 	dockerHubAccessToken = os.Getenv("DOCKERHUB_ACCESS_TOKEN")
 	retainBucket         = true // set to false in unit tests
 )
@@ -47,7 +51,21 @@ func getCacheRepoPrefix(prefix, suffix string) string {
 }
 
 type TemplateOverrides struct {
-	VpcID string
+	CreateSecretsKey       bool                            // create a KMS key for encrypting secrets, like the Pulumi stack secrets
+	EgressCIDRs            []string                        // CIDRs the task can connect to; all by default
+	EgressPorts            []int                           // TCP ports the task can connect to; all by default
+	IngressCIDRs           []string                        // CIDRs that can connect to the container ports; none by default
+	LogDestinationARN      string                          // destination for a subscription filter on the log group, like a Kinesis stream
+	LogDestinationRoleARN  string                          // role that CloudWatch Logs assumes to write to the destination; not for Lambda
+	LogRetentionDays       int                             // retention of the log group; 1 day by default
+	PermissionsBoundaryARN string                          // permissions boundary for the task role
+	PrivateSubnet          bool                            // the existing subnet has no internet gateway route; only with VpcID
+	RegistryCredentials    map[string]registry.Credentials // credentials for private registries, by registry host
+	SubnetID               string                          // existing subnet for the task; only with VpcID
+	SubnetIDs              []string                        // other existing subnets to remember, like those of the services; only with VpcID
+	TaskRoleARN            string                          // existing role for the task, instead of creating one
+	TaskRolePolicy         map[string]any                  // policy document for the task role, instead of AdministratorAccess
+	VpcID                  string                          // existing VPC, instead of creating one
 }
 
 func createTemplate(stack string, containers []types.Container, overrides TemplateOverrides, spot bool) *cloudformation.Template {
@@ -84,17 +102,32 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 		// ClusterName: ptr.String(PREFIX + "cluster" + SUFFIX), // optional
 	}
 
-	// 3. ECS capacity provider
-	capacityProvider := "FARGATE"
+	// 3. ECS capacity providers; both are associated so tasks can fall back to on-demand when Spot has no capacity
+	capacityProvider := awsecs.CapacityProviderFargate
 	if spot {
-		capacityProvider = "FARGATE_SPOT"
+		capacityProvider = awsecs.CapacityProviderFargateSpot
+	}
+	template.Outputs[outputs.Spot] = cloudformation.Output{
+		Value:       strconv.FormatBool(spot),
+		Description: ptr.String("Whether tasks run on Fargate Spot by default"),
+	}
+	capacityProviders := []string{
+		awsecs.CapacityProviderFargate,
+		awsecs.CapacityProviderFargateSpot,
+	}
+	const _gpuCapacityProvider = "GpuCapacityProvider"
+	var gpus uint32
+	for _, container := range containers {
+		gpus += container.Gpus
+	}
+	if gpus > 0 {
+		// Fargate doesn't support GPUs; see 10. for the EC2 capacity provider
+		capacityProviders = append(capacityProviders, cloudformation.Ref(_gpuCapacityProvider))
 	}
 	const _capacityProvider = "CapacityProvider"
 	template.Resources[_capacityProvider] = &ecs.ClusterCapacityProviderAssociations{
-		Cluster: cloudformation.Ref(_cluster),
-		CapacityProviders: []string{
-			capacityProvider,
-		},
+		Cluster:           cloudformation.Ref(_cluster),
+		CapacityProviders: capacityProviders,
 		DefaultCapacityProviderStrategy: []ecs.ClusterCapacityProviderAssociations_CapacityProviderStrategy{
 			{
 				CapacityProvider: capacityProvider,
@@ -105,16 +138,46 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 
 	// 4. CloudWatch log group
 	const _logGroup = "LogGroup"
+	retentionInDays := 1
+	if overrides.LogRetentionDays > 0 {
+		retentionInDays = overrides.LogRetentionDays
+		template.Outputs[outputs.LogRetentionDays] = cloudformation.Output{
+			Value:       strconv.Itoa(overrides.LogRetentionDays),
+			Description: ptr.String("Number of days the logs are kept"),
+		}
+	}
 	template.Resources[_logGroup] = &logs.LogGroup{
 		Tags: defaultTags,
 		// LogGroupName:    ptr.String(PREFIX + "log-group-test" + SUFFIX), // optional
-		RetentionInDays: ptr.Int(1),
+		RetentionInDays: ptr.Int(retentionInDays),
 		// Make sure the log group cannot be deleted while the cluster is up
 		AWSCloudFormationDependsOn: []string{
 			_cluster,
 		},
 	}
 
+	// 4b. CloudWatch subscription filter (optional)
+	if overrides.LogDestinationARN != "" {
+		var roleArn *string
+		if overrides.LogDestinationRoleARN != "" {
+			roleArn = ptr.String(overrides.LogDestinationRoleARN)
+			template.Outputs[outputs.LogDestinationRole] = cloudformation.Output{
+				Value:       overrides.LogDestinationRoleARN,
+				Description: ptr.String("ARN of the role that sends the logs to the destination"),
+			}
+		}
+		template.Outputs[outputs.LogDestinationARN] = cloudformation.Output{
+			Value:       overrides.LogDestinationARN,
+			Description: ptr.String("ARN of the destination of the logs"),
+		}
+		template.Resources["LogSubscriptionFilter"] = &logs.SubscriptionFilter{
+			DestinationArn: overrides.LogDestinationARN,
+			FilterPattern:  "", // all events
+			LogGroupName:   cloudformation.Ref(_logGroup),
+			RoleArn:        roleArn,
+		}
+	}
+
 	const _privateRepoSecret = "PrivateRepoSecret"
 	// 5. ECR pull-through cache rules
 	// TODO: Creating pull through cache rules isn't supported in the following Regions:
@@ -122,8 +185,11 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 	// * China (Ningxia) (cn-northwest-1)
 	// * AWS GovCloud (US-East) (us-gov-east-1)
 	// * AWS GovCloud (US-West) (us-gov-west-1)
+	registrySecrets := addRegistrySecrets(template, defaultTags, overrides.RegistryCredentials)
 	images := make([]string, 0, len(containers))
+	repositoryCredentials := make([]*ecs.TaskDefinition_RepositoryCredentials, 0, len(containers))
 	for _, task := range containers {
+		var repoCreds *ecs.TaskDefinition_RepositoryCredentials
 		image := task.Image
 		if repo, ok := strings.CutPrefix(image, awsecs.EcrPublicRegistry); ok {
 			const _pullThroughCache = "PullThroughCache"
@@ -166,11 +232,14 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 			}
 
 			image = cloudformation.Sub("${AWS::AccountId}.dkr.ecr.${AWS::Region}.amazonaws.com/" + dockerPublicPrefix + repo)
-		} else {
+		} else if secretID, ok := registrySecrets[registry.Host(image)]; ok {
 			// TODO: support pull through cache for other registries
-			// TODO: support private repos (with or without pull-through cache)
+			repoCreds = &ecs.TaskDefinition_RepositoryCredentials{
+				CredentialsParameter: cloudformation.RefPtr(secretID),
+			}
 		}
 		images = append(images, image)
+		repositoryCredentials = append(repositoryCredentials, repoCreds)
 	}
 
 	// 6. IAM roles for ECS task
@@ -229,6 +298,29 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 			},
 		})
 	}
+	if len(registrySecrets) > 0 {
+		secretArns := make([]string, 0, len(registrySecrets))
+		for _, secretID := range registrySecrets {
+			secretArns = append(secretArns, cloudformation.Ref(secretID))
+		}
+		sort.Strings(secretArns) // stable template
+		execPolicies = append(execPolicies, iam.Role_Policy{
+			// From https://docs.aws.amazon.com/AmazonECS/latest/developerguide/private-auth.html
+			PolicyName: "AllowGetRegistrySecrets",
+			PolicyDocument: map[string]any{
+				"Version": "2012-10-17",
+				"Statement": []map[string]any{
+					{
+						"Effect": "Allow",
+						"Action": []string{
+							"secretsmanager:GetSecretValue",
+						},
+						"Resource": secretArns,
+					},
+				},
+			},
+		})
+	}
 	const _executionRole = "ExecutionRole"
 	template.Resources[_executionRole] = &iam.Role{
 		Tags: defaultTags,
@@ -242,64 +334,22 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 
 	// 6b. IAM role for task (optional)
 	const _taskRole = "TaskRole"
-	template.Resources[_taskRole] = &iam.Role{
-		Tags: defaultTags,
-		// RoleName: ptr.String(PREFIX + "task-role" + SUFFIX), // optional
-		ManagedPolicyArns: []string{
-			"arn:aws:iam::aws:policy/AdministratorAccess", // TODO: make this configurable
-		},
-		AssumeRolePolicyDocument: assumeRolePolicyDocumentECS,
-		Policies: []iam.Role_Policy{
-			{
-				// From https://docs.aws.amazon.com/AmazonECS/latest/developerguide/task-iam-roles.html#ecs-exec-required-iam-permissions
-				PolicyName: "AllowExecuteCommand",
-				PolicyDocument: map[string]any{
-					"Version": "2012-10-17",
-					"Statement": []map[string]any{
-						{
-							"Effect": "Allow",
-							"Action": []string{
-								"ssmmessages:CreateDataChannel",
-								"ssmmessages:OpenDataChannel",
-								"ssmmessages:OpenControlChannel",
-								"ssmmessages:CreateControlChannel",
-							},
-							"Resource": "*", // TODO: restrict
-						},
-					},
-				},
-			},
-			{
-				PolicyName: "AllowPassRole",
-				PolicyDocument: map[string]any{
-					"Version": "2012-10-17",
-					"Statement": []map[string]any{
-						{
-							"Effect": "Allow",
-							"Action": []string{
-								"iam:PassRole",
-							},
-							"Resource": "*", // TODO: restrict to roles that are needed/created by the task
-						},
-					},
-				},
-			},
-			{
-				PolicyName: "AllowAssumeRole",
-				PolicyDocument: map[string]any{
-					"Version": "2012-10-17",
-					"Statement": []map[string]any{
-						{
-							"Effect": "Allow",
-							"Action": []string{
-								"sts:AssumeRole",
-							},
-							"Resource": "*",
-						},
-					},
-				},
-			},
-		},
+	taskRoleArn := cloudformation.RefPtr(_taskRole)
+	if overrides.TaskRoleARN != "" {
+		// The existing role needs the same trust and ECS Exec permissions as the one we'd create
+		taskRoleArn = ptr.String(overrides.TaskRoleARN)
+		template.Outputs[outputs.TaskRoleARN] = cloudformation.Output{
+			Value:       overrides.TaskRoleARN,
+			Description: ptr.String("ARN of the existing task role"),
+		}
+	} else {
+		template.Resources[_taskRole] = createTaskRole(defaultTags, assumeRolePolicyDocumentECS, overrides)
+		if overrides.PermissionsBoundaryARN != "" {
+			template.Outputs[outputs.PermissionsBoundary] = cloudformation.Output{
+				Value:       overrides.PermissionsBoundaryARN,
+				Description: ptr.String("ARN of the permissions boundary of the task role"),
+			}
+		}
 	}
 
 	// 7. ECS task definition
@@ -328,6 +378,9 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 	var containerDefinitions []ecs.TaskDefinition_ContainerDefinition
 	for i, container := range containers {
 		for _, v := range container.Volumes {
+			if slices.ContainsFunc(volumes, func(volume ecs.TaskDefinition_Volume) bool { return *volume.Name == v.Source }) {
+				continue // shared by multiple containers
+			}
 			volumes = append(volumes, ecs.TaskDefinition_Volume{
 				Name: ptr.String(v.Source),
 			})
@@ -374,6 +427,36 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 			}
 		}
 
+		var portMappings []ecs.TaskDefinition_PortMapping
+		for _, port := range container.Ports {
+			var hostPort *int
+			if gpus > 0 {
+				hostPort = ptr.Int(int(port.Target)) // bridge network mode; the security group is on the instance
+			}
+			portMappings = append(portMappings, ecs.TaskDefinition_PortMapping{
+				ContainerPort: ptr.Int(int(port.Target)),
+				HostPort:      hostPort,
+				Protocol:      ptr.String(portProtocol(port)),
+			})
+		}
+
+		var environment []ecs.TaskDefinition_KeyValuePair
+		for key, value := range container.Env {
+			environment = append(environment, ecs.TaskDefinition_KeyValuePair{
+				Name:  ptr.String(key),
+				Value: ptr.String(value),
+			})
+		}
+		sort.Slice(environment, func(i, j int) bool { return *environment[i].Name < *environment[j].Name }) // stable template
+
+		var resourceRequirements []ecs.TaskDefinition_ResourceRequirement
+		if container.Gpus > 0 {
+			resourceRequirements = []ecs.TaskDefinition_ResourceRequirement{{
+				Type:  "GPU",
+				Value: strconv.FormatUint(uint64(container.Gpus), 10),
+			}}
+		}
+
 		def := ecs.TaskDefinition_ContainerDefinition{
 			Name:        name,
 			Image:       images[i],
@@ -388,16 +471,25 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 					"awslogs-stream-prefix": awsecs.AwsLogsStreamPrefix,
 				},
 			},
-			VolumesFrom:      volumesFrom,
-			MountPoints:      mountPoints,
-			EntryPoint:       container.EntryPoint,
-			Command:          container.Command,
-			WorkingDirectory: container.WorkDir,
-			DependsOnProp:    dependsOn,
+			VolumesFrom:           volumesFrom,
+			MountPoints:           mountPoints,
+			EntryPoint:            container.EntryPoint,
+			Command:               container.Command,
+			Environment:           environment,
+			WorkingDirectory:      container.WorkDir,
+			DependsOnProp:         dependsOn,
+			PortMappings:          portMappings,
+			RepositoryCredentials: repositoryCredentials[i],
+			ResourceRequirements:  resourceRequirements,
 		}
 		containerDefinitions = append(containerDefinitions, def)
 	}
 
+	// GPU tasks run on EC2 instances in bridge network mode, so they can use the public IP of the instance
+	networkMode, compatibility := "awsvpc", "FARGATE"
+	if gpus > 0 {
+		networkMode, compatibility = "bridge", "EC2"
+	}
 	const _taskDefinition = "TaskDefinition"
 	template.Resources[_taskDefinition] = &ecs.TaskDefinition{
 		Tags: defaultTags,
@@ -410,14 +502,14 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 		Cpu:                     ptr.String(strconv.FormatUint(uint64(mCpu), 10)), // MilliCPU
 		ExecutionRoleArn:        cloudformation.RefPtr(_executionRole),
 		Memory:                  ptr.String(strconv.FormatUint(uint64(mib), 10)), // MiB
-		NetworkMode:             ptr.String("awsvpc"),
-		RequiresCompatibilities: []string{"FARGATE"},
-		TaskRoleArn:             cloudformation.RefPtr(_taskRole),
+		NetworkMode:             ptr.String(networkMode),
+		RequiresCompatibilities: []string{compatibility},
+		TaskRoleArn:             taskRoleArn,
 		// Family:                  cloudformation.SubPtr("${AWS::StackName}-TaskDefinition"), // optional, but needed to avoid TaskDef replacement
 	}
 
-	var vpcId *string
-	if overrides.VpcID == "" && createVpcResources {
+	var vpcId, subnetId *string
+	if overrides.VpcID == "" {
 		// 8a. a VPC
 		const _vpc = "VPC"
 		template.Resources[_vpc] = &ec2.VPC{
@@ -472,6 +564,8 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 			ServiceName:     cloudformation.Sub("com.amazonaws.${AWS::Region}.s3"),
 		}
 
+		subnetId = cloudformation.RefPtr(_subnet)
+
 		template.Outputs[outputs.SubnetID] = cloudformation.Output{
 			Value:       cloudformation.Ref(_subnet),
 			Description: ptr.String("ID of the subnet"),
@@ -480,29 +574,99 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 
 	if overrides.VpcID != "" {
 		vpcId = ptr.String(overrides.VpcID)
+
+		// Remember the existing VPC, so later updates of the stack don't create a new one
+		template.Outputs[outputs.VpcID] = cloudformation.Output{
+			Value:       overrides.VpcID,
+			Description: ptr.String("ID of the existing VPC"),
+		}
+		if overrides.SubnetID != "" {
+			subnetId = ptr.String(overrides.SubnetID)
+			template.Outputs[outputs.SubnetID] = cloudformation.Output{
+				Value:       overrides.SubnetID,
+				Description: ptr.String("ID of the existing subnet"),
+			}
+		}
+		if len(overrides.SubnetIDs) > 0 {
+			template.Outputs[outputs.SubnetIDs] = cloudformation.Output{
+				Value:       strings.Join(overrides.SubnetIDs, ","),
+				Description: ptr.String("IDs of the other existing subnets"),
+			}
+		}
+		if overrides.PrivateSubnet {
+			template.Outputs[outputs.PrivateSubnet] = cloudformation.Output{
+				Value:       "true",
+				Description: ptr.String("Whether the subnet is private, so tasks don't get a public IP"),
+			}
+		}
 	}
 
 	const _securityGroup = "SecurityGroup"
 	template.Resources[_securityGroup] = &ec2.SecurityGroup{
-		Tags:             defaultTags, // Name tag is ignored
-		GroupDescription: "Security group for the ECS task that allows all outbound and inbound traffic",
-		VpcId:            vpcId,
-		SecurityGroupIngress: []ec2.SecurityGroup_Ingress{
-			{
-				IpProtocol: "tcp",
-				FromPort:   ptr.Int(1),
-				ToPort:     ptr.Int(65535),
-				CidrIp:     ptr.String("0.0.0.0/0"), // from anywhere; FIXME: make optional and/or restrict to "my ip"
+		Tags:                 defaultTags, // Name tag is ignored
+		GroupDescription:     "Security group for the ECS task",
+		VpcId:                vpcId,
+		SecurityGroupIngress: createIngressRules(containers, overrides.IngressCIDRs),
+		SecurityGroupEgress:  createEgressRules(overrides.EgressCIDRs, overrides.EgressPorts), // nil allows all outbound traffic
+	}
+	if len(overrides.EgressCIDRs) > 0 {
+		template.Outputs[outputs.EgressCIDRs] = cloudformation.Output{
+			Value:       strings.Join(overrides.EgressCIDRs, ","),
+			Description: ptr.String("CIDRs the task can connect to"),
+		}
+	}
+
+	if overrides.CreateSecretsKey {
+		// 9. KMS key for encrypting secrets; this is $1 per month, so it's only created when it's used
+		const _secretsKey = "SecretsKey"
+		template.Resources[_secretsKey] = &kms.Key{
+			Tags:              defaultTags,
+			Description:       ptr.String("Key for encrypting the secrets in the deployment state"),
+			EnableKeyRotation: ptr.Bool(true),
+			KeyPolicy: map[string]any{
+				"Version": "2012-10-17",
+				"Statement": []map[string]any{
+					{
+						// Delegate access to IAM policies, like the task role's, as with the default key policy
+						"Effect": "Allow",
+						"Principal": map[string]any{
+							"AWS": cloudformation.Sub("arn:${AWS::Partition}:iam::${AWS::AccountId}:root"),
+						},
+						"Action":   "kms:*",
+						"Resource": "*",
+					},
+				},
 			},
-		},
-		// SecurityGroupEgress: []ec2.SecurityGroup_Egress{; FIXME: add ability to restrict outbound traffic
-		// 	{
-		// 		IpProtocol: "tcp",
-		// 		FromPort:   ptr.Int(1),
-		// 		ToPort:     ptr.Int(65535),
-		// 		// CidrIp:     ptr.String("
-		// 	},
-		// },
+			// Without the key, the secrets in the bucket can't be decrypted
+			AWSCloudFormationDeletionPolicy: bucketDeletionPolicy,
+		}
+
+		template.Outputs[outputs.SecretsKeyARN] = cloudformation.Output{
+			Value:       cloudformation.GetAtt(_secretsKey, "Arn"),
+			Description: ptr.String("ARN of the KMS key for secrets"),
+		}
+	}
+
+	if gpus > 0 {
+		// 10. EC2 capacity provider for GPU tasks
+		instance, err := awsecs.GetGpuInstance(gpus)
+		if err != nil {
+			panic(err) // should have been validated by the caller
+		}
+		// The instances go in the subnet of the stack, or else in the other existing subnets, like those of the services
+		subnetIds := overrides.SubnetIDs
+		if subnetId != nil {
+			subnetIds = []string{*subnetId}
+		}
+		if len(subnetIds) == 0 {
+			panic("GPU capacity needs a subnet; set SubnetID or SubnetIDs with VpcID") // should have been populated by the caller
+		}
+		addGpuCapacity(template, _gpuCapacityProvider, defaultTags, instance, subnetIds, cloudformation.Ref(_securityGroup), !overrides.PrivateSubnet)
+
+		template.Outputs[outputs.GpuCapacityProvider] = cloudformation.Output{
+			Value:       cloudformation.Ref(_gpuCapacityProvider),
+			Description: ptr.String("Name of the EC2 capacity provider for GPU tasks"),
+		}
 	}
 
 	// Declare stack outputs
@@ -529,3 +693,225 @@ func createTemplate(stack string, containers []types.Container, overrides Templa
 
 	return template
 }
+
+// addGpuCapacity adds an auto scaling group of GPU instances as the named capacity provider; it scales from zero when a task is started
+func addGpuCapacity(template *cloudformation.Template, name string, defaultTags []tags.Tag, instance awsecs.GpuInstance, subnetIds []string, securityGroupId string, publicIP bool) {
+	// 10a. IAM role for the container instances
+	const _instanceRole = "GpuInstanceRole"
+	template.Resources[_instanceRole] = &iam.Role{
+		Tags: defaultTags,
+		ManagedPolicyArns: []string{
+			"arn:aws:iam::aws:policy/service-role/AmazonEC2ContainerServiceforEC2Role",
+		},
+		AssumeRolePolicyDocument: map[string]any{
+			"Version": "2012-10-17",
+			"Statement": []map[string]any{
+				{
+					"Effect": "Allow",
+					"Principal": map[string]any{
+						"Service": []string{
+							"ec2.amazonaws.com",
+						},
+					},
+					"Action": []string{
+						"sts:AssumeRole",
+					},
+				},
+			},
+		},
+	}
+	const _instanceProfile = "GpuInstanceProfile"
+	template.Resources[_instanceProfile] = &iam.InstanceProfile{
+		Roles: []string{cloudformation.Ref(_instanceRole)},
+	}
+
+	// 10b. launch template with the ECS-optimized GPU AMI, which has the NVIDIA drivers
+	const _launchTemplate = "GpuLaunchTemplate"
+	template.Resources[_launchTemplate] = &ec2.LaunchTemplate{
+		LaunchTemplateData: &ec2.LaunchTemplate_LaunchTemplateData{
+			IamInstanceProfile: &ec2.LaunchTemplate_IamInstanceProfile{
+				Arn: ptr.String(cloudformation.GetAtt(_instanceProfile, "Arn")),
+			},
+			ImageId:      ptr.String("{{resolve:ssm:" + awsecs.GpuImageParameter + "}}"),
+			InstanceType: ptr.String(instance.Type),
+			NetworkInterfaces: []ec2.LaunchTemplate_NetworkInterface{
+				{
+					AssociatePublicIpAddress: ptr.Bool(publicIP),
+					DeviceIndex:              ptr.Int(0),
+					Groups:                   []string{securityGroupId},
+					// the subnet is picked by the auto scaling group
+				},
+			},
+			UserData: cloudformation.Base64Ptr(cloudformation.Sub("#!/bin/bash\necho ECS_CLUSTER=${Cluster} >> /etc/ecs/ecs.config\n")),
+		},
+	}
+
+	// 10c. auto scaling group, which is scaled by ECS
+	const _autoScalingGroup = "GpuAutoScalingGroup"
+	template.Resources[_autoScalingGroup] = &autoscaling.AutoScalingGroup{
+		LaunchTemplate: &autoscaling.AutoScalingGroup_LaunchTemplateSpecification{
+			LaunchTemplateId: cloudformation.RefPtr(_launchTemplate),
+			Version:          cloudformation.GetAtt(_launchTemplate, "LatestVersionNumber"),
+		},
+		MaxSize:           "4", // TODO: make configurable
+		MinSize:           "0",
+		VPCZoneIdentifier: subnetIds,
+	}
+
+	// 10d. ECS capacity provider
+	template.Resources[name] = &ecs.CapacityProvider{
+		Tags: defaultTags,
+		AutoScalingGroupProvider: &ecs.CapacityProvider_AutoScalingGroupProvider{
+			AutoScalingGroupArn: cloudformation.Ref(_autoScalingGroup),
+			ManagedScaling: &ecs.CapacityProvider_ManagedScaling{
+				Status:         ptr.String("ENABLED"),
+				TargetCapacity: ptr.Int(100),
+			},
+			ManagedTerminationProtection: ptr.String("DISABLED"),
+		},
+	}
+}
+
+// createTaskRole creates the role for the task, with AdministratorAccess unless a policy is given
+func createTaskRole(defaultTags []tags.Tag, assumeRolePolicyDocument map[string]any, overrides TemplateOverrides) *iam.Role {
+	policies := []iam.Role_Policy{
+		{
+			// From https://docs.aws.amazon.com/AmazonECS/latest/developerguide/task-iam-roles.html#ecs-exec-required-iam-permissions
+			PolicyName: "AllowExecuteCommand",
+			PolicyDocument: map[string]any{
+				"Version": "2012-10-17",
+				"Statement": []map[string]any{
+					{
+						"Effect": "Allow",
+						"Action": []string{
+							"ssmmessages:CreateDataChannel",
+							"ssmmessages:OpenDataChannel",
+							"ssmmessages:OpenControlChannel",
+							"ssmmessages:CreateControlChannel",
+						},
+						"Resource": "*", // TODO: restrict
+					},
+				},
+			},
+		},
+	}
+
+	var managedPolicyArns []string
+	if overrides.TaskRolePolicy != nil {
+		// The policy is expected to restrict iam:PassRole and sts:AssumeRole itself
+		policies = append(policies, iam.Role_Policy{
+			PolicyName:     "TaskRolePolicy",
+			PolicyDocument: overrides.TaskRolePolicy,
+		})
+	} else {
+		managedPolicyArns = []string{
+			"arn:aws:iam::aws:policy/AdministratorAccess",
+		}
+		policies = append(policies,
+			iam.Role_Policy{
+				PolicyName: "AllowPassRole",
+				PolicyDocument: map[string]any{
+					"Version": "2012-10-17",
+					"Statement": []map[string]any{
+						{
+							"Effect": "Allow",
+							"Action": []string{
+								"iam:PassRole",
+							},
+							"Resource": "*", // TODO: restrict to roles that are needed/created by the task
+						},
+					},
+				},
+			},
+			iam.Role_Policy{
+				PolicyName: "AllowAssumeRole",
+				PolicyDocument: map[string]any{
+					"Version": "2012-10-17",
+					"Statement": []map[string]any{
+						{
+							"Effect": "Allow",
+							"Action": []string{
+								"sts:AssumeRole",
+							},
+							"Resource": "*",
+						},
+					},
+				},
+			},
+		)
+	}
+
+	var permissionsBoundary *string
+	if overrides.PermissionsBoundaryARN != "" {
+		permissionsBoundary = ptr.String(overrides.PermissionsBoundaryARN)
+	}
+
+	return &iam.Role{
+		Tags: defaultTags,
+		// RoleName: ptr.String(PREFIX + "task-role" + SUFFIX), // optional
+		ManagedPolicyArns:        managedPolicyArns,
+		AssumeRolePolicyDocument: assumeRolePolicyDocument,
+		PermissionsBoundary:      permissionsBoundary,
+		Policies:                 policies,
+	}
+}
+
+func portProtocol(port types.Port) string {
+	if port.Protocol == "" {
+		return "tcp"
+	}
+	return strings.ToLower(port.Protocol)
+}
+
+// createIngressRules allows the given CIDRs to connect to the ports of the containers
+func createIngressRules(containers []types.Container, cidrs []string) []ec2.SecurityGroup_Ingress {
+	var rules []ec2.SecurityGroup_Ingress
+	for _, container := range containers {
+		for _, port := range container.Ports {
+			for _, cidr := range cidrs {
+				rule := ec2.SecurityGroup_Ingress{
+					IpProtocol: portProtocol(port),
+					FromPort:   ptr.Int(int(port.Target)),
+					ToPort:     ptr.Int(int(port.Target)),
+				}
+				if strings.Contains(cidr, ":") {
+					rule.CidrIpv6 = ptr.String(cidr)
+				} else {
+					rule.CidrIp = ptr.String(cidr)
+				}
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+// createEgressRules restricts outbound traffic to the given CIDRs and TCP ports; the task still needs to reach ECR and
+// CloudWatch Logs, so the ports should include 443
+func createEgressRules(cidrs []string, ports []int) []ec2.SecurityGroup_Egress {
+	if len(ports) > 0 && len(cidrs) == 0 {
+		cidrs = []string{"0.0.0.0/0"}
+	}
+	var rules []ec2.SecurityGroup_Egress
+	for _, cidr := range cidrs {
+		rule := ec2.SecurityGroup_Egress{
+			IpProtocol: "-1", // all protocols and ports
+		}
+		if strings.Contains(cidr, ":") {
+			rule.CidrIpv6 = ptr.String(cidr)
+		} else {
+			rule.CidrIp = ptr.String(cidr)
+		}
+		if len(ports) == 0 {
+			rules = append(rules, rule)
+			continue
+		}
+		for _, port := range ports {
+			rule.IpProtocol = "tcp"
+			rule.FromPort = ptr.Int(port)
+			rule.ToPort = ptr.Int(port)
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
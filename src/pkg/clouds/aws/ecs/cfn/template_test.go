@@ -1,6 +1,20 @@
 package cfn
 
-import "testing"
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/awslabs/goformation/v7/cloudformation/autoscaling"
+	"github.com/awslabs/goformation/v7/cloudformation/ec2"
+	"github.com/awslabs/goformation/v7/cloudformation/ecs"
+	"github.com/awslabs/goformation/v7/cloudformation/iam"
+	"github.com/awslabs/goformation/v7/cloudformation/logs"
+	"github.com/awslabs/goformation/v7/cloudformation/secretsmanager"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn/outputs"
+	"github.com/defang-io/defang/src/pkg/registry"
+	"github.com/defang-io/defang/src/pkg/types"
+)
 
 func TestGetCacheRepoPrefix(t *testing.T) {
 	// Test cases
@@ -35,3 +49,285 @@ func TestGetCacheRepoPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTemplateSecretsKey(t *testing.T) {
+	containers := []types.Container{{Image: "alpine", Platform: "linux/amd64"}}
+	for _, createSecretsKey := range []bool{false, true} {
+		template := createTemplate("test", containers, TemplateOverrides{CreateSecretsKey: createSecretsKey}, false)
+		if _, ok := template.Resources["SecretsKey"]; ok != createSecretsKey {
+			t.Errorf("expected SecretsKey resource: %v, got %v", createSecretsKey, ok)
+		}
+		if _, ok := template.Outputs[outputs.SecretsKeyARN]; ok != createSecretsKey {
+			t.Errorf("expected %s output: %v, got %v", outputs.SecretsKeyARN, createSecretsKey, ok)
+		}
+	}
+}
+
+func TestCreateTemplateCapacityProvider(t *testing.T) {
+	containers := []types.Container{{Image: "alpine", Platform: "linux/amd64"}}
+	for spot, want := range map[bool]string{false: "FARGATE", true: "FARGATE_SPOT"} {
+		template := createTemplate("test", containers, TemplateOverrides{}, spot)
+		association := template.Resources["CapacityProvider"].(*ecs.ClusterCapacityProviderAssociations)
+		if len(association.CapacityProviders) != 2 {
+			t.Errorf("expected both capacity providers for the on-demand fallback, got %v", association.CapacityProviders)
+		}
+		if got := association.DefaultCapacityProviderStrategy[0].CapacityProvider; got != want {
+			t.Errorf("expected default capacity provider %q with spot=%v, got %q", want, spot, got)
+		}
+		if got := template.Outputs[outputs.Spot].Value; got != strconv.FormatBool(spot) {
+			t.Errorf("expected spot output %v, got %v", spot, got)
+		}
+	}
+}
+
+func TestCreateTemplateGpus(t *testing.T) {
+	containers := []types.Container{{Image: "alpine", Platform: "linux/amd64"}}
+	template := createTemplate("test", containers, TemplateOverrides{}, true)
+	for _, resource := range []string{"GpuCapacityProvider", "GpuAutoScalingGroup", "GpuLaunchTemplate"} {
+		if _, ok := template.Resources[resource]; ok {
+			t.Errorf("unexpected %s resource", resource)
+		}
+	}
+
+	containers = []types.Container{{Image: "alpine", Platform: "linux/amd64", Gpus: 2, Ports: []types.Port{{Target: 8080}}}}
+	template = createTemplate("test", containers, TemplateOverrides{}, true)
+	association := template.Resources["CapacityProvider"].(*ecs.ClusterCapacityProviderAssociations)
+	if len(association.CapacityProviders) != 3 {
+		t.Errorf("expected the GPU capacity provider, got %v", association.CapacityProviders)
+	}
+	launchTemplate := template.Resources["GpuLaunchTemplate"].(*ec2.LaunchTemplate)
+	if got := *launchTemplate.LaunchTemplateData.InstanceType; got != "g4dn.12xlarge" {
+		t.Errorf("expected instance type g4dn.12xlarge, got %q", got)
+	}
+	if _, ok := template.Outputs[outputs.GpuCapacityProvider]; !ok {
+		t.Errorf("expected %s output", outputs.GpuCapacityProvider)
+	}
+	taskDef := template.Resources["TaskDefinition"].(*ecs.TaskDefinition)
+	if *taskDef.NetworkMode != "bridge" || taskDef.RequiresCompatibilities[0] != "EC2" {
+		t.Errorf("expected bridge network mode on EC2, got %q on %v", *taskDef.NetworkMode, taskDef.RequiresCompatibilities)
+	}
+	container := taskDef.ContainerDefinitions[0]
+	if len(container.ResourceRequirements) != 1 || container.ResourceRequirements[0].Type != "GPU" || container.ResourceRequirements[0].Value != "2" {
+		t.Errorf("expected 2 GPUs, got %v", container.ResourceRequirements)
+	}
+	if hostPort := container.PortMappings[0].HostPort; hostPort == nil || *hostPort != 8080 {
+		t.Errorf("expected host port 8080, got %v", hostPort)
+	}
+}
+
+func TestCreateTemplateGpusExistingVpc(t *testing.T) {
+	containers := []types.Container{{Image: "alpine", Platform: "linux/amd64", Gpus: 1}}
+
+	template := createTemplate("test", containers, TemplateOverrides{VpcID: "vpc-123", SubnetID: "subnet-456"}, true)
+	autoScalingGroup := template.Resources["GpuAutoScalingGroup"].(*autoscaling.AutoScalingGroup)
+	if !slices.Equal(autoScalingGroup.VPCZoneIdentifier, []string{"subnet-456"}) {
+		t.Errorf("expected subnet-456, got %v", autoScalingGroup.VPCZoneIdentifier)
+	}
+
+	template = createTemplate("test", containers, TemplateOverrides{VpcID: "vpc-123", SubnetIDs: []string{"subnet-456", "subnet-789"}}, true)
+	autoScalingGroup = template.Resources["GpuAutoScalingGroup"].(*autoscaling.AutoScalingGroup)
+	if !slices.Equal(autoScalingGroup.VPCZoneIdentifier, []string{"subnet-456", "subnet-789"}) {
+		t.Errorf("expected the other subnets, got %v", autoScalingGroup.VPCZoneIdentifier)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic without a subnet")
+		}
+	}()
+	createTemplate("test", containers, TemplateOverrides{VpcID: "vpc-123"}, true)
+}
+
+func TestCreateTemplateExistingVpc(t *testing.T) {
+	containers := []types.Container{{Image: "alpine", Platform: "linux/amd64"}}
+
+	template := createTemplate("test", containers, TemplateOverrides{}, false)
+	if _, ok := template.Resources["VPC"]; !ok {
+		t.Error("expected VPC resource")
+	}
+	if _, ok := template.Outputs[outputs.VpcID]; ok {
+		t.Errorf("unexpected %s output", outputs.VpcID)
+	}
+
+	template = createTemplate("test", containers, TemplateOverrides{VpcID: "vpc-123", SubnetID: "subnet-456", SubnetIDs: []string{"subnet-456", "subnet-789"}, PrivateSubnet: true}, false)
+	for _, resource := range []string{"VPC", "Subnet", "InternetGateway"} {
+		if _, ok := template.Resources[resource]; ok {
+			t.Errorf("unexpected %s resource", resource)
+		}
+	}
+	for key, want := range map[string]string{outputs.VpcID: "vpc-123", outputs.SubnetID: "subnet-456", outputs.SubnetIDs: "subnet-456,subnet-789", outputs.PrivateSubnet: "true"} {
+		if got := template.Outputs[key].Value; got != want {
+			t.Errorf("expected %s output %q, got %v", key, want, got)
+		}
+	}
+}
+
+func TestCreateTemplateTaskRole(t *testing.T) {
+	containers := []types.Container{{Image: "alpine", Platform: "linux/amd64"}}
+
+	template := createTemplate("test", containers, TemplateOverrides{}, false)
+	role, ok := template.Resources["TaskRole"].(*iam.Role)
+	if !ok {
+		t.Fatal("expected TaskRole resource")
+	}
+	if len(role.ManagedPolicyArns) != 1 {
+		t.Errorf("expected AdministratorAccess, got %v", role.ManagedPolicyArns)
+	}
+
+	policy := map[string]any{"Version": "2012-10-17"}
+	template = createTemplate("test", containers, TemplateOverrides{TaskRolePolicy: policy, PermissionsBoundaryARN: "arn:aws:iam::123456789012:policy/boundary"}, false)
+	role = template.Resources["TaskRole"].(*iam.Role)
+	if len(role.ManagedPolicyArns) != 0 {
+		t.Errorf("expected no managed policies, got %v", role.ManagedPolicyArns)
+	}
+	if role.PermissionsBoundary == nil || *role.PermissionsBoundary != "arn:aws:iam::123456789012:policy/boundary" {
+		t.Errorf("expected permissions boundary, got %v", role.PermissionsBoundary)
+	}
+	for _, p := range role.Policies {
+		if p.PolicyName == "AllowPassRole" || p.PolicyName == "AllowAssumeRole" {
+			t.Errorf("unexpected %s policy", p.PolicyName)
+		}
+	}
+
+	template = createTemplate("test", containers, TemplateOverrides{TaskRoleARN: "arn:aws:iam::123456789012:role/cd"}, false)
+	if _, ok := template.Resources["TaskRole"]; ok {
+		t.Error("unexpected TaskRole resource")
+	}
+}
+
+func TestCreateTemplateSecurityGroup(t *testing.T) {
+	containers := []types.Container{{Image: "alpine", Platform: "linux/amd64", Ports: []types.Port{{Target: 80}, {Target: 53, Protocol: "udp"}}}}
+
+	template := createTemplate("test", containers, TemplateOverrides{}, false)
+	sg := template.Resources["SecurityGroup"].(*ec2.SecurityGroup)
+	if len(sg.SecurityGroupIngress) != 0 {
+		t.Errorf("expected no ingress rules, got %v", sg.SecurityGroupIngress)
+	}
+	if sg.SecurityGroupEgress != nil {
+		t.Errorf("expected default egress, got %v", sg.SecurityGroupEgress)
+	}
+	if _, ok := template.Outputs[outputs.EgressCIDRs]; ok {
+		t.Error("unexpected egress CIDRs output")
+	}
+
+	template = createTemplate("test", containers, TemplateOverrides{IngressCIDRs: []string{"1.2.3.4/32", "::1/128"}, EgressCIDRs: []string{"10.0.0.0/8"}}, false)
+	sg = template.Resources["SecurityGroup"].(*ec2.SecurityGroup)
+	if len(sg.SecurityGroupIngress) != 4 {
+		t.Fatalf("expected 4 ingress rules, got %d", len(sg.SecurityGroupIngress))
+	}
+	if rule := sg.SecurityGroupIngress[2]; rule.IpProtocol != "udp" || *rule.FromPort != 53 || *rule.CidrIp != "1.2.3.4/32" {
+		t.Errorf("unexpected ingress rule %+v", rule)
+	}
+	if rule := sg.SecurityGroupIngress[3]; rule.CidrIpv6 == nil || *rule.CidrIpv6 != "::1/128" {
+		t.Errorf("unexpected ingress rule %+v", rule)
+	}
+	if len(sg.SecurityGroupEgress) != 1 || *sg.SecurityGroupEgress[0].CidrIp != "10.0.0.0/8" {
+		t.Errorf("unexpected egress rules %v", sg.SecurityGroupEgress)
+	}
+	if got := template.Outputs[outputs.EgressCIDRs].Value; got != "10.0.0.0/8" {
+		t.Errorf("expected egress CIDRs output, got %v", got)
+	}
+
+	template = createTemplate("test", containers, TemplateOverrides{EgressPorts: []int{443, 5432}}, false)
+	sg = template.Resources["SecurityGroup"].(*ec2.SecurityGroup)
+	if len(sg.SecurityGroupEgress) != 2 {
+		t.Fatalf("expected 2 egress rules, got %d", len(sg.SecurityGroupEgress))
+	}
+	if rule := sg.SecurityGroupEgress[1]; rule.IpProtocol != "tcp" || *rule.FromPort != 5432 || *rule.ToPort != 5432 || *rule.CidrIp != "0.0.0.0/0" {
+		t.Errorf("unexpected egress rule %+v", rule)
+	}
+}
+
+func TestCreateTemplateLogGroup(t *testing.T) {
+	containers := []types.Container{{Image: "alpine", Platform: "linux/amd64"}}
+
+	template := createTemplate("test", containers, TemplateOverrides{}, false)
+	if got := *template.Resources["LogGroup"].(*logs.LogGroup).RetentionInDays; got != 1 {
+		t.Errorf("expected default retention of 1 day, got %d", got)
+	}
+	if _, ok := template.Resources["LogSubscriptionFilter"]; ok {
+		t.Error("unexpected LogSubscriptionFilter resource")
+	}
+	if _, ok := template.Outputs[outputs.LogRetentionDays]; ok {
+		t.Error("unexpected log retention output")
+	}
+
+	template = createTemplate("test", containers, TemplateOverrides{LogRetentionDays: 30, LogDestinationARN: "arn:aws:kinesis:us-west-2:123456789012:stream/logs", LogDestinationRoleARN: "arn:aws:iam::123456789012:role/logs"}, false)
+	if got := *template.Resources["LogGroup"].(*logs.LogGroup).RetentionInDays; got != 30 {
+		t.Errorf("expected retention of 30 days, got %d", got)
+	}
+	filter, ok := template.Resources["LogSubscriptionFilter"].(*logs.SubscriptionFilter)
+	if !ok {
+		t.Fatal("expected LogSubscriptionFilter resource")
+	}
+	if filter.RoleArn == nil || *filter.RoleArn != "arn:aws:iam::123456789012:role/logs" {
+		t.Errorf("expected role ARN, got %v", filter.RoleArn)
+	}
+	for key, want := range map[string]string{outputs.LogRetentionDays: "30", outputs.LogDestinationARN: "arn:aws:kinesis:us-west-2:123456789012:stream/logs", outputs.LogDestinationRole: "arn:aws:iam::123456789012:role/logs"} {
+		if got := template.Outputs[key].Value; got != want {
+			t.Errorf("expected output %s to be %q, got %v", key, want, got)
+		}
+	}
+}
+
+func TestCreateTemplateRegistryCredentials(t *testing.T) {
+	containers := []types.Container{
+		{Image: "ghcr.io/org/app", Platform: "linux/amd64"},
+		{Image: "alpine", Platform: "linux/amd64"},
+	}
+	creds := map[string]registry.Credentials{"ghcr.io": {Username: "user", Password: "token"}}
+
+	template := createTemplate("test", containers, TemplateOverrides{RegistryCredentials: creds}, false)
+	if _, ok := template.Resources["RegistrySecretGhcrIo"].(*secretsmanager.Secret); !ok {
+		t.Fatal("expected RegistrySecretGhcrIo resource")
+	}
+	if param := template.Parameters["RegistryCredentialsGhcrIo"]; param.NoEcho == nil || !*param.NoEcho {
+		t.Error("expected NoEcho parameter for the credentials")
+	}
+	def := template.Resources["TaskDefinition"].(*ecs.TaskDefinition)
+	if def.ContainerDefinitions[0].RepositoryCredentials == nil {
+		t.Error("expected repository credentials for the private image")
+	}
+	if def.ContainerDefinitions[1].RepositoryCredentials != nil {
+		t.Error("unexpected repository credentials for the public image")
+	}
+
+	params := registryParameters(creds)
+	if len(params) != 1 || *params[0].ParameterValue != `{"username":"user","password":"token"}` {
+		t.Errorf("unexpected parameters %v", params)
+	}
+}
+
+func TestRegistryLogicalName(t *testing.T) {
+	tests := map[string]string{
+		"ghcr.io":                      "GhcrIo",
+		"docker.io":                    "DockerIo",
+		"registry.gitlab.com":          "RegistryGitlabCom",
+		"my-registry.example.com:5000": "MyRegistryExampleCom5000",
+		"123456789012.dkr.ecr.us-west-2.amazonaws.com": "123456789012DkrEcrUsWest2AmazonawsCom",
+	}
+	for host, want := range tests {
+		if got := registryLogicalName(host); got != want {
+			t.Errorf("registryLogicalName(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestCreateTemplateSidecars(t *testing.T) {
+	containers := []types.Container{
+		{Image: "alpine", Name: "main", Env: map[string]string{"B": "2", "A": "1"}, Volumes: []types.TaskVolume{{Source: "data", Target: "/data", ReadOnly: true}}},
+		{Image: "busybox", Name: "init", Volumes: []types.TaskVolume{{Source: "data", Target: "/data"}}},
+	}
+	template := createTemplate("test", containers, TemplateOverrides{}, true)
+	taskDef := template.Resources["TaskDefinition"].(*ecs.TaskDefinition)
+	if len(taskDef.Volumes) != 1 || *taskDef.Volumes[0].Name != "data" {
+		t.Errorf("expected a single shared volume, got %v", taskDef.Volumes)
+	}
+	env := taskDef.ContainerDefinitions[0].Environment
+	if len(env) != 2 || *env[0].Name != "A" || *env[1].Name != "B" {
+		t.Errorf("expected the sorted environment of the main container, got %v", env)
+	}
+	if len(taskDef.ContainerDefinitions[1].Environment) != 0 {
+		t.Errorf("expected no environment for the helper, got %v", taskDef.ContainerDefinitions[1].Environment)
+	}
+}
@@ -12,20 +12,25 @@ const (
 	DockerRegistry    = "docker.io"
 	EcrPublicRegistry = "public.ecr.aws"
 	ProjectName       = types.ProjectName
+
+	CapacityProviderFargate     = "FARGATE"
+	CapacityProviderFargateSpot = "FARGATE_SPOT"
 )
 
 type TaskArn = types.TaskID
 
 type AwsEcs struct {
 	aws.Aws
-	BucketName      string
-	ClusterName     string
-	LogGroupARN     string
-	SecurityGroupID string
-	Spot            bool
-	SubNetID        string
-	TaskDefARN      string
-	VpcID           string
+	BucketName          string
+	ClusterName         string
+	GpuCapacityProvider string // EC2 capacity provider for tasks with GPUs; empty for Fargate
+	LogGroupARN         string
+	PrivateSubnet       bool // tasks don't get a public IP; the subnet needs a NAT gateway to pull images
+	SecurityGroupID     string
+	Spot                bool // run tasks on Fargate Spot; Run falls back to on-demand when Spot has no capacity
+	SubNetID            string
+	TaskDefARN          string
+	VpcID               string
 }
 
 func PlatformToArchOS(platform string) (string, string) {
@@ -0,0 +1,93 @@
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/defang-io/defang/src/pkg/clouds/aws"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/region"
+)
+
+// Event is an ECS EventBridge event, as delivered to a CloudWatch log group.
+// See https://docs.aws.amazon.com/AmazonECS/latest/developerguide/ecs_cwe_events.html
+type Event struct {
+	DetailType string      `json:"detail-type"`
+	Resources  []string    `json:"resources"`
+	Detail     EventDetail `json:"detail"`
+}
+
+type EventDetail struct {
+	ClusterArn    string `json:"clusterArn"`
+	EventName     string `json:"eventName"`  // for "ECS Service Action" and "ECS Deployment State Change"
+	Reason        string `json:"reason"`     // for "ECS Deployment State Change"
+	Group         string `json:"group"`      // for "ECS Task State Change", like "service:name"
+	LastStatus    string `json:"lastStatus"` // for "ECS Task State Change"
+	StoppedReason string `json:"stoppedReason"`
+	TaskArn       string `json:"taskArn"`
+}
+
+func ParseEvent(message []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(message, &event); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(event.DetailType, "ECS ") {
+		return nil, errors.New("not an ECS event: " + event.DetailType)
+	}
+	return &event, nil
+}
+
+// ServiceName returns the name of the ECS service this event is about, or "" if it's not about a service.
+func (e *Event) ServiceName() string {
+	if name, ok := strings.CutPrefix(e.Detail.Group, "service:"); ok {
+		return name
+	}
+	for _, resource := range e.Resources {
+		// arn:aws:ecs:region:account:service/cluster/name
+		if _, path, ok := strings.Cut(resource, ":service/"); ok {
+			return path[strings.LastIndexByte(path, '/')+1:]
+		}
+	}
+	return ""
+}
+
+// ClusterArn returns the ARN of the ECS cluster this event is about.
+func (e *Event) ClusterArn() string {
+	if e.Detail.ClusterArn != "" {
+		return e.Detail.ClusterArn
+	}
+	for _, resource := range e.Resources {
+		// arn:aws:ecs:region:account:service/cluster/name
+		if prefix, path, ok := strings.Cut(resource, ":service/"); ok {
+			cluster, _, _ := strings.Cut(path, "/")
+			return prefix + ":cluster/" + cluster
+		}
+	}
+	return ""
+}
+
+// GetServiceReplicas returns the running and desired number of tasks of the given ECS service.
+func GetServiceReplicas(ctx context.Context, clusterArn, service string) (running, desired int32, err error) {
+	if clusterArn == "" {
+		return 0, 0, errors.New("cluster ARN is empty")
+	}
+	cfg, err := aws.LoadDefaultConfig(ctx, region.FromArn(clusterArn))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dso, err := ecs.NewFromConfig(cfg).DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  &clusterArn,
+		Services: []string{service},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(dso.Services) == 0 {
+		return 0, 0, errors.New("service not found: " + service)
+	}
+	return dso.Services[0].RunningCount, dso.Services[0].DesiredCount, nil
+}
@@ -0,0 +1,53 @@
+package ecs
+
+import "testing"
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		wantService string
+		wantCluster string
+		wantErr     bool
+	}{
+		{
+			name:        "deployment state change",
+			message:     `{"detail-type":"ECS Deployment State Change","resources":["arn:aws:ecs:us-west-2:123456789012:service/cluster-1/app-1234567"],"detail":{"eventName":"SERVICE_DEPLOYMENT_COMPLETED"}}`,
+			wantService: "app-1234567",
+			wantCluster: "arn:aws:ecs:us-west-2:123456789012:cluster/cluster-1",
+		},
+		{
+			name:        "task state change",
+			message:     `{"detail-type":"ECS Task State Change","resources":["arn:aws:ecs:us-west-2:123456789012:task/cluster-1/abc"],"detail":{"clusterArn":"arn:aws:ecs:us-west-2:123456789012:cluster/cluster-1","group":"service:app-1234567","lastStatus":"RUNNING"}}`,
+			wantService: "app-1234567",
+			wantCluster: "arn:aws:ecs:us-west-2:123456789012:cluster/cluster-1",
+		},
+		{
+			name:    "not an ECS event",
+			message: `{"detail-type":"EC2 Instance State-change Notification"}`,
+			wantErr: true,
+		},
+		{
+			name:    "not JSON",
+			message: `hello`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseEvent([]byte(tt.message))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := event.ServiceName(); got != tt.wantService {
+				t.Errorf("ServiceName() = %q, want %q", got, tt.wantService)
+			}
+			if got := event.ClusterArn(); got != tt.wantCluster {
+				t.Errorf("ClusterArn() = %q, want %q", got, tt.wantCluster)
+			}
+		})
+	}
+}
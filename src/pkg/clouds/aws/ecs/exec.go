@@ -0,0 +1,161 @@
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/defang-io/defang/src/pkg/clouds/aws"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/region"
+)
+
+const sessionManagerPlugin = "session-manager-plugin"
+
+var (
+	ErrExecuteCommandDisabled = errors.New("ECS Exec is not enabled")
+	ErrNoSessionManagerPlugin = errors.New("the AWS Session Manager plugin is required; see https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html")
+)
+
+func (a *AwsEcs) Exec(ctx context.Context, taskArn TaskArn, args ...string) error {
+	return ExecuteCommand(ctx, taskArn, func(name string) bool { return name == ContainerName }, args...)
+}
+
+// FindServiceTask returns a running task of the first ECS service in the cluster for which match returns true.
+func (a *AwsEcs) FindServiceTask(ctx context.Context, cluster string, match func(service string) bool) (TaskArn, error) {
+	cfg, err := a.LoadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ecsClient := ecs.NewFromConfig(cfg)
+
+	paginator := ecs.NewListServicesPaginator(ecsClient, &ecs.ListServicesInput{Cluster: &cluster})
+	for paginator.HasMorePages() {
+		lso, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, serviceArn := range lso.ServiceArns {
+			service := serviceArn[strings.LastIndexByte(serviceArn, '/')+1:]
+			if !match(service) {
+				continue
+			}
+			lto, err := ecsClient.ListTasks(ctx, &ecs.ListTasksInput{
+				Cluster:       &cluster,
+				ServiceName:   &service,
+				DesiredStatus: types.DesiredStatusRunning,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(lto.TaskArns) == 0 {
+				return nil, fmt.Errorf("no running tasks for service %q", service)
+			}
+			return &lto.TaskArns[0], nil
+		}
+	}
+	return nil, nil // not found
+}
+
+// ExecuteCommand starts an interactive ECS Exec session in the container of a running task for which match returns true.
+func ExecuteCommand(ctx context.Context, taskArn TaskArn, match func(container string) bool, args ...string) error {
+	if len(args) == 0 {
+		args = []string{"sh"}
+	}
+	if _, err := exec.LookPath(sessionManagerPlugin); err != nil {
+		return ErrNoSessionManagerPlugin
+	}
+
+	region := region.FromArn(*taskArn)
+	cfg, err := aws.LoadDefaultConfig(ctx, region)
+	if err != nil {
+		return err
+	}
+	ecsClient := ecs.NewFromConfig(cfg)
+
+	cluster, taskID := SplitClusterTask(taskArn)
+	container, err := findTaskContainer(ctx, ecsClient, taskArn, match)
+	if err != nil {
+		return err
+	}
+
+	eco, err := ecsClient.ExecuteCommand(ctx, &ecs.ExecuteCommandInput{
+		Cluster:     &cluster,
+		Task:        taskArn,
+		Container:   container.Name,
+		Command:     ptr.String(quoteArgs(args)), // not run in a shell, but split like one
+		Interactive: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Same as the AWS CLI: "ecs:<cluster>_<task ID>_<container runtime ID>"
+	target := fmt.Sprintf("ecs:%s_%s_%s", cluster, taskID, *container.RuntimeId)
+	return runSessionManagerPlugin(ctx, eco.Session, region, map[string]string{"Target": target}, "https://ecs."+string(region)+".amazonaws.com")
+}
+
+// quoteArgs joins the arguments into a single command line, quoting them like a POSIX shell so the agent splits them back
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@%+,") == "" {
+		return arg // nothing to quote
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// findTaskContainer returns the running container of the task for which match returns true; the Session Manager target needs its runtime ID.
+func findTaskContainer(ctx context.Context, ecsClient *ecs.Client, taskArn TaskArn, match func(container string) bool) (types.Container, error) {
+	cluster, taskID := SplitClusterTask(taskArn)
+	dto, err := ecsClient.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: &cluster,
+		Tasks:   []string{taskID},
+	})
+	if err != nil {
+		return types.Container{}, err
+	}
+	if len(dto.Tasks) == 0 {
+		return types.Container{}, fmt.Errorf("task %q not found", taskID)
+	}
+	task := dto.Tasks[0]
+	if !task.EnableExecuteCommand {
+		return types.Container{}, fmt.Errorf("%w for task %q", ErrExecuteCommandDisabled, taskID)
+	}
+	for _, c := range task.Containers {
+		if c.Name != nil && match(*c.Name) && c.RuntimeId != nil {
+			return c, nil
+		}
+	}
+	return types.Container{}, fmt.Errorf("no running container found in task %q", taskID)
+}
+
+// runSessionManagerPlugin hands the session over to the AWS Session Manager plugin, like the AWS CLI does.
+func runSessionManagerPlugin(ctx context.Context, session any, region aws.Region, request any, endpoint string) error {
+	sessionJson, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	requestJson, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, sessionManagerPlugin, string(sessionJson), string(region), "StartSession", os.Getenv("AWS_PROFILE"), string(requestJson), endpoint)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
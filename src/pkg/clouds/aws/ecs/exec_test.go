@@ -0,0 +1,22 @@
+package ecs
+
+import "testing"
+
+func TestQuoteArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"sh"}, "sh"},
+		{[]string{"ls", "-la", "/app/data"}, "ls -la /app/data"},
+		{[]string{"sh", "-c", "echo a b"}, "sh -c 'echo a b'"},
+		{[]string{"echo", "it's"}, `echo 'it'\''s'`},
+		{[]string{"echo", ""}, "echo ''"},
+		{[]string{"echo", "$HOME"}, "echo '$HOME'"},
+	}
+	for _, tt := range tests {
+		if got := quoteArgs(tt.args); got != tt.want {
+			t.Errorf("quoteArgs(%q) = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,16 @@ import (
 type CpuUnits = uint
 type MemoryMiB = uint
 
+const (
+	FargateMaxCpus      = 16
+	FargateMaxMemoryMiB = 122880
+	FargateMinCpus      = 0.25
+
+	FargateQuotaService      = "fargate"
+	FargateOnDemandQuotaCode = "L-3032A538" // Fargate On-Demand vCPU resource count
+	FargateSpotQuotaCode     = "L-36FBB829" // Fargate Spot vCPU resource count
+)
+
 func makeMinMaxCeil(value float64, min, max, step uint) uint {
 	if value <= float64(min) || math.IsNaN(value) {
 		return min
@@ -0,0 +1,34 @@
+package ecs
+
+import "fmt"
+
+// Fargate doesn't support GPUs, so GPU tasks run on EC2 instances of the G4dn family
+const (
+	GpuImageParameter = "/aws/service/ecs/optimized-ami/amazon-linux-2/gpu/recommended/image_id"
+	GpuQuotaCode      = "L-DB2E81BA" // Running On-Demand G and VT instances, in vCPUs
+	GpuQuotaService   = "ec2"
+)
+
+type GpuInstance struct {
+	Type  string
+	Gpus  uint32
+	VCpus uint32
+}
+
+// From https://aws.amazon.com/ec2/instance-types/g4/; smallest first
+var gpuInstances = []GpuInstance{
+	{Type: "g4dn.xlarge", Gpus: 1, VCpus: 4},
+	{Type: "g4dn.12xlarge", Gpus: 4, VCpus: 48},
+	{Type: "g4dn.metal", Gpus: 8, VCpus: 96},
+}
+
+// GetGpuInstance returns the smallest instance type with at least the given number of GPUs
+func GetGpuInstance(gpus uint32) (GpuInstance, error) {
+	for _, instance := range gpuInstances {
+		if instance.Gpus >= gpus {
+			return instance, nil
+		}
+	}
+	max := gpuInstances[len(gpuInstances)-1]
+	return GpuInstance{}, fmt.Errorf("%d GPUs exceeds the maximum of %d per task (%s)", gpus, max.Gpus, max.Type)
+}
@@ -0,0 +1,27 @@
+package ecs
+
+import "testing"
+
+func TestGetGpuInstance(t *testing.T) {
+	tests := []struct {
+		gpus     uint32
+		wantType string
+		wantErr  bool
+	}{
+		{1, "g4dn.xlarge", false},
+		{2, "g4dn.12xlarge", false},
+		{4, "g4dn.12xlarge", false},
+		{8, "g4dn.metal", false},
+		{9, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := GetGpuInstance(tt.gpus)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetGpuInstance(%v) error = %v, wantErr %v", tt.gpus, err, tt.wantErr)
+		}
+		if got.Type != tt.wantType {
+			t.Errorf("GetGpuInstance(%v) = %v, want %v", tt.gpus, got.Type, tt.wantType)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/smithy-go/ptr"
@@ -28,7 +29,13 @@ func (a AwsEcs) Info(ctx context.Context, id TaskArn) (*types.TaskInfo, error) {
 	// b, err := json.MarshalIndent(ti, "", "  ")
 	// println(string(b))
 
-	if len(ti.Tasks) == 0 || len(ti.Tasks[0].Attachments) == 0 {
+	if len(ti.Tasks) == 0 {
+		return nil, errors.New("task not found")
+	}
+	if len(ti.Tasks[0].Attachments) == 0 && ti.Tasks[0].ContainerInstanceArn != nil {
+		return a.instanceInfo(ctx, cfg, *ti.Tasks[0].ContainerInstanceArn) // GPU tasks use the network of the instance
+	}
+	if len(ti.Tasks[0].Attachments) == 0 {
 		return nil, errors.New("no attachments")
 	}
 
@@ -58,3 +65,31 @@ func (a AwsEcs) Info(ctx context.Context, id TaskArn) (*types.TaskInfo, error) {
 	}
 	return nil, nil // no public IP?
 }
+
+// instanceInfo returns the public IP of the EC2 instance of a task in bridge network mode
+func (a AwsEcs) instanceInfo(ctx context.Context, cfg aws.Config, containerInstanceArn string) (*types.TaskInfo, error) {
+	dcio, err := ecs.NewFromConfig(cfg).DescribeContainerInstances(ctx, &ecs.DescribeContainerInstancesInput{
+		Cluster:            ptr.String(a.ClusterName),
+		ContainerInstances: []string{containerInstanceArn},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dcio.ContainerInstances) == 0 || dcio.ContainerInstances[0].Ec2InstanceId == nil {
+		return nil, errors.New("no container instance")
+	}
+	dio, err := ec2.NewFromConfig(cfg).DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{*dcio.ContainerInstances[0].Ec2InstanceId},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dio.Reservations) == 0 || len(dio.Reservations[0].Instances) == 0 {
+		return nil, errors.New("no instance")
+	}
+	ip := ptr.ToString(dio.Reservations[0].Instances[0].PublicIpAddress)
+	if ip == "" {
+		return nil, nil // private subnet
+	}
+	return &types.TaskInfo{IP: ip}, nil
+}
@@ -169,29 +169,39 @@ func Query(ctx context.Context, lgi LogGroupInput, start time.Time, end time.Tim
 	if lgi.LogStreamNamePrefix != "" {
 		prefix = &lgi.LogStreamNamePrefix
 	}
+	var pattern *string
+	if lgi.LogEventFilterPattern != "" {
+		pattern = &lgi.LogEventFilterPattern
+	}
 	cw := cloudwatchlogs.NewFromConfig(cfg)
-	fleo, err := cw.FilterLogEvents(ctx, &cloudwatchlogs.FilterLogEventsInput{
+	input := &cloudwatchlogs.FilterLogEventsInput{
 		StartTime:           ptr.Int64(start.UnixMilli()),
 		EndTime:             ptr.Int64(end.UnixMilli()),
+		FilterPattern:       pattern,
 		LogGroupIdentifier:  &logGroupIdentifier,
 		LogStreamNamePrefix: prefix,
 		LogStreamNames:      lgi.LogStreamNames,
-	})
-	if err != nil {
-		return nil, err
 	}
-	events := make([]LogEvent, len(fleo.Events))
-	for i, e := range fleo.Events {
-		events[i] = LogEvent{
-			IngestionTime:      e.IngestionTime,
-			LogGroupIdentifier: &logGroupIdentifier,
-			Message:            e.Message,
-			Timestamp:          e.Timestamp,
-			LogStreamName:      e.LogStreamName,
+	var events []LogEvent
+	for {
+		fleo, err := cw.FilterLogEvents(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range fleo.Events {
+			events = append(events, LogEvent{
+				IngestionTime:      e.IngestionTime,
+				LogGroupIdentifier: &logGroupIdentifier,
+				Message:            e.Message,
+				Timestamp:          e.Timestamp,
+				LogStreamName:      e.LogStreamName,
+			})
+		}
+		if fleo.NextToken == nil {
+			return events, nil
 		}
+		input.NextToken = fleo.NextToken
 	}
-	// TODO: handle pagination using NextToken
-	return events, nil
 }
 
 func startTail(ctx context.Context, slti *cloudwatchlogs.StartLiveTailInput) (EventStream, error) {
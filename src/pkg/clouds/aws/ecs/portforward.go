@@ -0,0 +1,126 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/defang-io/defang/src/pkg/clouds/aws"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/region"
+)
+
+const executeCommandAgent = "ExecuteCommandAgent"
+
+// StartPortForwarding forwards localPort to remotePort of the container of a running task for which match returns true,
+// or to remotePort of host if host is not empty, until the context is canceled.
+func StartPortForwarding(ctx context.Context, taskArn TaskArn, match func(container string) bool, host string, localPort, remotePort int) error {
+	if _, err := exec.LookPath(sessionManagerPlugin); err != nil {
+		return ErrNoSessionManagerPlugin
+	}
+
+	region := region.FromArn(*taskArn)
+	cfg, err := aws.LoadDefaultConfig(ctx, region)
+	if err != nil {
+		return err
+	}
+
+	cluster, taskID := SplitClusterTask(taskArn)
+	container, err := findTaskContainer(ctx, ecs.NewFromConfig(cfg), taskArn, match)
+	if err != nil {
+		return err
+	}
+
+	document := "AWS-StartPortForwardingSession"
+	parameters := map[string][]string{
+		"portNumber":      {strconv.Itoa(remotePort)},
+		"localPortNumber": {strconv.Itoa(localPort)},
+	}
+	if host != "" {
+		document = "AWS-StartPortForwardingSessionToRemoteHost"
+		parameters["host"] = []string{host}
+	}
+
+	// Same as the AWS CLI: "ecs:<cluster>_<task ID>_<container runtime ID>"
+	target := fmt.Sprintf("ecs:%s_%s_%s", cluster, taskID, *container.RuntimeId)
+	sso, err := ssm.NewFromConfig(cfg).StartSession(ctx, &ssm.StartSessionInput{
+		Target:       &target,
+		DocumentName: &document,
+		Parameters:   parameters,
+	})
+	if err != nil {
+		return err
+	}
+
+	session := types.Session{SessionId: sso.SessionId, StreamUrl: sso.StreamUrl, TokenValue: sso.TokenValue}
+	request := map[string]any{"Target": target, "DocumentName": document, "Parameters": parameters}
+	return runSessionManagerPlugin(ctx, session, region, request, "https://ssm."+string(region)+".amazonaws.com")
+}
+
+// GetTaskSubnetID returns the ID of the subnet of the network interface of the task.
+func GetTaskSubnetID(ctx context.Context, taskArn TaskArn) (string, error) {
+	task, err := describeTask(ctx, taskArn)
+	if err != nil {
+		return "", err
+	}
+	for _, attachment := range task.Attachments {
+		for _, detail := range attachment.Details {
+			if detail.Name != nil && *detail.Name == "subnetId" && detail.Value != nil {
+				return *detail.Value, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no subnet found for task %q", GetTaskID(taskArn))
+}
+
+// WaitForExecuteCommandAgent waits until the ECS Exec agent of the task is running, which can take a while after the task started.
+func WaitForExecuteCommandAgent(ctx context.Context, taskArn TaskArn, poll time.Duration) error {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		task, err := describeTask(ctx, taskArn)
+		if err != nil {
+			return err
+		}
+		if task.LastStatus != nil && isTaskTerminalState(*task.LastStatus) {
+			return taskFailure{string(task.StopCode), ptr.ToString(task.StoppedReason)}
+		}
+		for _, container := range task.Containers {
+			for _, agent := range container.ManagedAgents {
+				if agent.Name == executeCommandAgent && agent.LastStatus != nil && *agent.LastStatus == "RUNNING" {
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func describeTask(ctx context.Context, taskArn TaskArn) (types.Task, error) {
+	cfg, err := aws.LoadDefaultConfig(ctx, region.FromArn(*taskArn))
+	if err != nil {
+		return types.Task{}, err
+	}
+	cluster, taskID := SplitClusterTask(taskArn)
+	dto, err := ecs.NewFromConfig(cfg).DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: &cluster,
+		Tasks:   []string{taskID},
+	})
+	if err != nil {
+		return types.Task{}, err
+	}
+	if len(dto.Tasks) == 0 {
+		return types.Task{}, fmt.Errorf("task %q not found", taskID)
+	}
+	return dto.Tasks[0], nil
+}
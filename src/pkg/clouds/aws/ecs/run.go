@@ -3,7 +3,10 @@ package ecs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,10 +15,19 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/aws/smithy-go/ptr"
+	"github.com/defang-io/defang/src/pkg/term"
 )
 
 const taskCount = 1
 
+// Tags set on the tasks started by Run
+const (
+	TagCommand   = "Command"
+	TagEtag      = "Etag"
+	TagStartedAt = "StartedAt"
+	TagStartedBy = "StartedBy"
+)
+
 func (a *AwsEcs) PopulateVPCandSubnetID(ctx context.Context, vpcID, subnetID string) error {
 	cfg, err := a.LoadConfig(ctx)
 	if err != nil {
@@ -23,7 +35,7 @@ func (a *AwsEcs) PopulateVPCandSubnetID(ctx context.Context, vpcID, subnetID str
 	}
 
 	if vpcID != "" && subnetID == "" {
-		subnetID, err = getPublicSubnetId(ctx, cfg, vpcID)
+		subnetID, err = getSubnetId(ctx, cfg, vpcID, !a.PrivateSubnet)
 	} else if vpcID == "" && subnetID != "" {
 		vpcID, err = getSubnetVPCId(ctx, cfg, subnetID)
 	}
@@ -34,6 +46,11 @@ func (a *AwsEcs) PopulateVPCandSubnetID(ctx context.Context, vpcID, subnetID str
 }
 
 func (a *AwsEcs) Run(ctx context.Context, env map[string]string, cmd ...string) (TaskArn, error) {
+	return a.RunWithTags(ctx, env, nil, cmd...)
+}
+
+// RunWithTags is like Run but adds the given tags to the task, in addition to the StartedAt, StartedBy, and Command tags
+func (a *AwsEcs) RunWithTags(ctx context.Context, env map[string]string, tags map[string]string, cmd ...string) (TaskArn, error) {
 	// a.Refresh(ctx)
 
 	cfg, err := a.LoadConfig(ctx)
@@ -56,16 +73,20 @@ func (a *AwsEcs) Run(ctx context.Context, env map[string]string, cmd ...string)
 	// }
 
 	securityGroups := []string{a.SecurityGroupID} // TODO: only if ports are mapped
+	assignPublicIp := types.AssignPublicIpEnabled // only works with public subnets
+	if a.PrivateSubnet {
+		assignPublicIp = types.AssignPublicIpDisabled
+	}
 	rti := ecs.RunTaskInput{
-		Count:          ptr.Int32(taskCount),
-		LaunchType:     types.LaunchTypeFargate,
-		TaskDefinition: ptr.String(a.TaskDefARN),
-		PropagateTags:  types.PropagateTagsTaskDefinition,
-		Cluster:        ptr.String(a.ClusterName),
+		Count:                ptr.Int32(taskCount),
+		TaskDefinition:       ptr.String(a.TaskDefARN),
+		PropagateTags:        types.PropagateTagsTaskDefinition,
+		Cluster:              ptr.String(a.ClusterName),
+		EnableExecuteCommand: true, // for `crun exec`; the task role has the ssmmessages permissions
 		NetworkConfiguration: &types.NetworkConfiguration{
 			AwsvpcConfiguration: &types.AwsVpcConfiguration{
-				AssignPublicIp: types.AssignPublicIpEnabled, // only works with public subnets
-				Subnets:        []string{a.SubNetID},        // TODO: make configurable; must this match the VPC of the SecGroup?
+				AssignPublicIp: assignPublicIp,
+				Subnets:        []string{a.SubNetID}, // TODO: make configurable; must this match the VPC of the SecGroup?
 				SecurityGroups: securityGroups,
 			},
 		},
@@ -78,30 +99,63 @@ func (a *AwsEcs) Run(ctx context.Context, env map[string]string, cmd ...string)
 					Name:        ptr.String(ContainerName),
 					Command:     cmd,
 					Environment: pairs,
-					// ResourceRequirements:; TODO: make configurable, support GPUs
 					// EnvironmentFiles: ,
 				},
 			},
 		},
-		Tags: []types.Tag{ //TODO: add tags to the task
+		Tags: []types.Tag{
 			{
-				Key:   ptr.String("StartedAt"),
+				Key:   ptr.String(TagStartedAt),
 				Value: ptr.String(time.Now().Format(time.RFC3339)),
 			},
 			{
-				Key:   ptr.String("StartedBy"),
+				Key:   ptr.String(TagStartedBy),
 				Value: ptr.String(os.Getenv("USER")),
 			},
 		},
 	}
+	if len(cmd) > 0 {
+		rti.Tags = append(rti.Tags, types.Tag{
+			Key:   ptr.String(TagCommand),
+			Value: ptr.String(cmd[0]), // the arguments might be too long for a tag value
+		})
+	}
+	for key, value := range tags {
+		rti.Tags = append(rti.Tags, types.Tag{
+			Key:   ptr.String(key),
+			Value: ptr.String(value),
+		})
+	}
 
-	ecsOutput, err := ecs.NewFromConfig(cfg).RunTask(ctx, &rti)
+	ecsClient := ecs.NewFromConfig(cfg)
+	if a.GpuCapacityProvider != "" {
+		rti.NetworkConfiguration = nil // the task uses the network of the GPU instance
+		return runTask(ctx, ecsClient, rti, a.GpuCapacityProvider)
+	}
+	if a.Spot {
+		taskArn, err := runTask(ctx, ecsClient, rti, CapacityProviderFargateSpot)
+		if !isCapacityFailure(err) {
+			return taskArn, err
+		}
+		term.Warn(" ! No Fargate Spot capacity available; retrying on on-demand:", err)
+	}
+	return runTask(ctx, ecsClient, rti, CapacityProviderFargate)
+}
+
+func runTask(ctx context.Context, ecsClient *ecs.Client, rti ecs.RunTaskInput, capacityProvider string) (TaskArn, error) {
+	rti.CapacityProviderStrategy = []types.CapacityProviderStrategyItem{
+		{
+			CapacityProvider: ptr.String(capacityProvider),
+			Weight:           1,
+		},
+	}
+	ecsOutput, err := ecsClient.RunTask(ctx, &rti)
 	if err != nil {
 		return nil, err
 	}
 	failures := make([]error, len(ecsOutput.Failures))
 	for i, f := range ecsOutput.Failures {
-		failures[i] = taskFailure{*f.Reason, *f.Detail}
+		failures[i] = taskFailure{ptr.ToString(f.Reason), ptr.ToString(f.Detail)}
 	}
 	if err := errors.Join(failures...); err != nil || len(ecsOutput.Tasks) == 0 {
 		return nil, err
@@ -111,7 +165,14 @@ func (a *AwsEcs) Run(ctx context.Context, env map[string]string, cmd ...string)
 	return TaskArn(ecsOutput.Tasks[0].TaskArn), nil
 }
 
-func getPublicSubnetId(ctx context.Context, cfg aws.Config, vpcId string) (string, error) {
+// isCapacityFailure returns true if the task could not be placed because there was no capacity, eg. "Capacity is unavailable at this time"
+func isCapacityFailure(err error) bool {
+	var tf taskFailure
+	return errors.As(err, &tf) && strings.Contains(strings.ToLower(tf.Reason), "capacity")
+}
+
+// getSubnetId returns a public or private subnet of the VPC; subnets that don't map public IPs on launch are assumed to be private
+func getSubnetId(ctx context.Context, cfg aws.Config, vpcId string, public bool) (string, error) {
 	subnetsOutput, err := ec2.NewFromConfig(cfg).DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
 		Filters: []ec2types.Filter{
 			{
@@ -120,13 +181,20 @@ func getPublicSubnetId(ctx context.Context, cfg aws.Config, vpcId string) (strin
 			},
 			{
 				Name:   ptr.String("map-public-ip-on-launch"),
-				Values: []string{"true"},
+				Values: []string{strconv.FormatBool(public)},
 			},
 		},
 	})
 	if err != nil {
 		return "", err
 	}
+	if len(subnetsOutput.Subnets) == 0 {
+		kind := "private"
+		if public {
+			kind = "public"
+		}
+		return "", fmt.Errorf("no %s subnet found in VPC %q", kind, vpcId)
+	}
 	return *subnetsOutput.Subnets[0].SubnetId, nil // TODO: make configurable/deterministic
 }
 
@@ -0,0 +1,27 @@
+package ecs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsCapacityFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"other error", errors.New("AccessDeniedException"), false},
+		{"other failure", taskFailure{"MISSING", "arn:aws:ecs:us-west-2:123456789012:container-instance/abc"}, false},
+		{"capacity", taskFailure{"Capacity is unavailable at this time. Please try again later or in a different availability zone", ""}, true},
+		{"joined", errors.Join(taskFailure{"Capacity is unavailable at this time", ""}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCapacityFailure(tt.err); got != tt.want {
+				t.Errorf("isCapacityFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
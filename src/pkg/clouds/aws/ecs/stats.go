@@ -0,0 +1,43 @@
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// ServiceStats is a Container Insights performance log event for an ECS service.
+// See https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/Container-Insights-reference-performance-logs-ECS.html
+type ServiceStats struct {
+	Type             string
+	ClusterName      string
+	ServiceName      string
+	CpuReserved      float64 // CPU units
+	CpuUtilized      float64 // CPU units
+	MemoryReserved   float64 // MiB
+	MemoryUtilized   float64 // MiB
+	DesiredTaskCount int32
+	RunningTaskCount int32
+}
+
+// ContainerInsightsLogGroup returns the name of the log group with the Container Insights performance logs for the cluster.
+func ContainerInsightsLogGroup(clusterName string) string {
+	return "/aws/ecs/containerinsights/" + clusterName + "/performance"
+}
+
+// QueryServiceStats returns the Container Insights stats for the services in the given performance log group, oldest first.
+func QueryServiceStats(ctx context.Context, logGroupARN string, start time.Time) ([]ServiceStats, error) {
+	events, err := Query(ctx, LogGroupInput{LogGroupARN: logGroupARN, LogEventFilterPattern: `{ $.Type = "Service" }`}, start, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]ServiceStats, 0, len(events))
+	for _, event := range events {
+		var ss ServiceStats
+		if event.Message == nil || json.Unmarshal([]byte(*event.Message), &ss) != nil || ss.Type != "Service" {
+			continue
+		}
+		stats = append(stats, ss)
+	}
+	return stats, nil
+}
@@ -0,0 +1,89 @@
+package ecs
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/smithy-go/ptr"
+)
+
+// TaskInfo describes a running or recently stopped task; ECS only keeps stopped tasks for about an hour.
+type TaskInfo struct {
+	TaskArn    TaskArn
+	Status     string // last status, like RUNNING or STOPPED
+	CreatedAt  time.Time
+	StoppedAt  time.Time // zero if the task is still running
+	StopReason string
+	ExitCode   *int32 // exit code of the main container, if it has exited
+	Tags       map[string]string
+}
+
+// ListTasks returns the running and recently stopped tasks in the cluster, newest first.
+func (a *AwsEcs) ListTasks(ctx context.Context) ([]TaskInfo, error) {
+	cfg, err := a.LoadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ecsClient := ecs.NewFromConfig(cfg)
+
+	var tasks []TaskInfo
+	for _, desiredStatus := range []types.DesiredStatus{types.DesiredStatusRunning, types.DesiredStatusStopped} {
+		paginator := ecs.NewListTasksPaginator(ecsClient, &ecs.ListTasksInput{
+			Cluster:       &a.ClusterName,
+			DesiredStatus: desiredStatus,
+		})
+		for paginator.HasMorePages() {
+			lto, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if len(lto.TaskArns) == 0 {
+				continue
+			}
+			// ListTasks returns at most 100 tasks per page, which is also the limit for DescribeTasks
+			dto, err := ecsClient.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+				Cluster: &a.ClusterName,
+				Tasks:   lto.TaskArns,
+				Include: []types.TaskField{types.TaskFieldTags},
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, task := range dto.Tasks {
+				tasks = append(tasks, newTaskInfo(task))
+			}
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].CreatedAt.After(tasks[j].CreatedAt) })
+	return tasks, nil
+}
+
+func newTaskInfo(task types.Task) TaskInfo {
+	info := TaskInfo{
+		TaskArn:    task.TaskArn,
+		Status:     ptr.ToString(task.LastStatus),
+		StopReason: ptr.ToString(task.StoppedReason),
+		Tags:       make(map[string]string, len(task.Tags)),
+	}
+	if task.CreatedAt != nil {
+		info.CreatedAt = *task.CreatedAt
+	}
+	if task.StoppedAt != nil {
+		info.StoppedAt = *task.StoppedAt
+	}
+	for _, container := range task.Containers {
+		if container.Name != nil && *container.Name == ContainerName {
+			info.ExitCode = container.ExitCode
+		}
+	}
+	for _, tag := range task.Tags {
+		if tag.Key != nil && tag.Value != nil {
+			info.Tags[*tag.Key] = *tag.Value
+		}
+	}
+	return info
+}
@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
+)
+
+// GetServiceQuota returns the value of a quota for the account, like "L-DB2E81BA" of "ec2"; the default value if it was never increased.
+func GetServiceQuota(ctx context.Context, cfg aws.Config, serviceCode, quotaCode string) (float64, error) {
+	svc := servicequotas.NewFromConfig(cfg)
+
+	gsqo, err := svc.GetServiceQuota(ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: &serviceCode,
+		QuotaCode:   &quotaCode,
+	})
+	var noSuchResource *types.NoSuchResourceException
+	if errors.As(err, &noSuchResource) {
+		gdsqo, err := svc.GetAWSDefaultServiceQuota(ctx, &servicequotas.GetAWSDefaultServiceQuotaInput{
+			ServiceCode: &serviceCode,
+			QuotaCode:   &quotaCode,
+		})
+		if err != nil {
+			return 0, err
+		}
+		return quotaValue(gdsqo.Quota), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return quotaValue(gsqo.Quota), nil
+}
+
+func quotaValue(quota *types.ServiceQuota) float64 {
+	if quota == nil || quota.Value == nil {
+		return 0
+	}
+	return *quota.Value
+}
@@ -1,14 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/defang-io/defang/src/pkg/clouds/aws"
+	"github.com/defang-io/defang/src/pkg/http"
 	"github.com/defang-io/defang/src/pkg/term"
+	"github.com/defang-io/defang/src/pkg/types"
 )
 
+// MyIP can be used instead of a CIDR to allow the public IP address of the caller
+const MyIP = "myip"
+
 type Region = aws.Region
 
 func ParseMemory(memory string) uint64 {
@@ -79,3 +87,39 @@ func ParseEnvFile(filename string, env map[string]string) (map[string]string, er
 	}
 	return parseEnvFile(string(bytes), env), nil
 }
+
+// ParsePort parses a port like "8080" or "53/udp"
+func ParsePort(port string) (types.Port, error) {
+	target, protocol, _ := strings.Cut(port, "/")
+	switch strings.ToLower(protocol) {
+	case "", "tcp", "udp":
+	default:
+		return types.Port{}, fmt.Errorf("invalid protocol: %q", protocol)
+	}
+	p, err := strconv.ParseUint(target, 10, 16)
+	if err != nil || p == 0 {
+		return types.Port{}, fmt.Errorf("invalid port: %q", target)
+	}
+	return types.Port{Target: uint16(p), Protocol: strings.ToLower(protocol)}, nil
+}
+
+// resolveCIDRs validates the CIDRs and replaces "myip" with the public IP address of the caller
+func resolveCIDRs(ctx context.Context, cidrs []string) ([]string, error) {
+	resolved := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if strings.EqualFold(cidr, MyIP) {
+			ip, err := http.GetPublicIP(ctx)
+			if err != nil {
+				return nil, err
+			}
+			cidr = ip + "/32"
+			if strings.Contains(ip, ":") {
+				cidr = ip + "/128"
+			}
+		} else if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, cidr)
+	}
+	return resolved, nil
+}
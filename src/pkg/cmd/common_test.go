@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"os"
 	"testing"
+
+	"github.com/defang-io/defang/src/pkg/types"
 )
 
 func TestParseMemory(t *testing.T) {
@@ -119,3 +122,42 @@ key4=crlf`+"\r\n", env)
 		t.Errorf("expected 'crlf', got %q", env["key4"])
 	}
 }
+
+func TestParsePort(t *testing.T) {
+	testCases := []struct {
+		port    string
+		want    types.Port
+		wantErr bool
+	}{
+		{port: "8080", want: types.Port{Target: 8080}},
+		{port: "53/udp", want: types.Port{Target: 53, Protocol: "udp"}},
+		{port: "443/TCP", want: types.Port{Target: 443, Protocol: "tcp"}},
+		{port: "0", wantErr: true},
+		{port: "65536", wantErr: true},
+		{port: "80/sctp", wantErr: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.port, func(t *testing.T) {
+			got, err := ParsePort(tC.port)
+			if (err != nil) != tC.wantErr {
+				t.Fatalf("expected error %v, got %v", tC.wantErr, err)
+			}
+			if got != tC.want {
+				t.Errorf("expected %v, got %v", tC.want, got)
+			}
+		})
+	}
+}
+
+func TestResolveCIDRs(t *testing.T) {
+	cidrs, err := resolveCIDRs(context.Background(), []string{"10.0.0.0/8", "::/0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cidrs) != 2 {
+		t.Errorf("expected 2 CIDRs, got %v", cidrs)
+	}
+	if _, err := resolveCIDRs(context.Background(), []string{"10.0.0.1"}); err == nil {
+		t.Error("expected error for IP without prefix length")
+	}
+}
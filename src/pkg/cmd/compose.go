@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+	compose "github.com/compose-spec/compose-go/v2/types"
+	"github.com/defang-io/defang/src/pkg/cli"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/types"
+)
+
+// LoadCompose reads a Compose file and returns the containers of a single task: the main service, which is the one that
+// no other service depends on, followed by its helpers, which share the network and volumes of the task like sidecars.
+func LoadCompose(path string) ([]types.Container, error) {
+	project, err := cli.ComposeLoader{ComposeFilePath: path}.LoadWithDefaultProjectName(types.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	return composeContainers(project)
+}
+
+func composeContainers(project *compose.Project) ([]types.Container, error) {
+	main, err := mainService(project.Services)
+	if err != nil {
+		return nil, err
+	}
+	// The main container is overridden by Run(), so it must have the name the drivers expect
+	containerName := func(service string) string {
+		if service == main {
+			return ecs.ContainerName
+		}
+		return service
+	}
+
+	helpers := slices.DeleteFunc(project.ServiceNames(), func(name string) bool { return name == main })
+
+	var containers []types.Container
+	var platform string
+	for _, name := range append([]string{main}, helpers...) {
+		svccfg := project.Services[name]
+		if name != main && name == ecs.ContainerName {
+			return nil, fmt.Errorf("service %q: the name %q is reserved for the main service", name, ecs.ContainerName)
+		}
+		if svccfg.Image == "" {
+			return nil, fmt.Errorf("service %q: missing image; building images is not supported", name)
+		}
+		if len(containers) == 0 {
+			platform = svccfg.Platform
+		} else if svccfg.Platform != platform {
+			return nil, fmt.Errorf("service %q: all services must have the same platform", name)
+		}
+
+		container := types.Container{
+			Image:      svccfg.Image,
+			Name:       containerName(name),
+			Platform:   svccfg.Platform,
+			Essential:  ptr.Bool(name == main), // the task stops when the main container exits
+			EntryPoint: svccfg.Entrypoint,
+			Command:    svccfg.Command,
+		}
+		if svccfg.WorkingDir != "" {
+			container.WorkDir = &svccfg.WorkingDir
+		}
+
+		if svccfg.Deploy != nil {
+			resource := svccfg.Deploy.Resources.Reservations
+			if resource == nil {
+				resource = svccfg.Deploy.Resources.Limits
+			}
+			if resource != nil {
+				if resource.NanoCPUs != "" {
+					cpus, err := strconv.ParseFloat(resource.NanoCPUs, 32)
+					if err != nil {
+						return nil, fmt.Errorf("service %q: invalid cpus: %w", name, err)
+					}
+					container.Cpus = float32(cpus)
+				}
+				container.Memory = uint64(resource.MemoryBytes)
+			}
+		}
+
+		for key, value := range svccfg.Environment {
+			if value == nil {
+				v, ok := os.LookupEnv(key)
+				if !ok {
+					continue // exclude missing env vars, like docker does
+				}
+				value = &v
+			}
+			if container.Env == nil {
+				container.Env = make(map[string]string)
+			}
+			container.Env[key] = *value
+		}
+
+		for _, port := range svccfg.Ports {
+			container.Ports = append(container.Ports, types.Port{Target: uint16(port.Target), Protocol: port.Protocol})
+		}
+
+		for _, volume := range svccfg.Volumes {
+			if volume.Type != compose.VolumeTypeVolume || volume.Source == "" {
+				return nil, fmt.Errorf("service %q: only named volumes are supported, not %s %q", name, volume.Type, volume.Source)
+			}
+			container.Volumes = append(container.Volumes, types.TaskVolume{Source: volume.Source, Target: volume.Target, ReadOnly: volume.ReadOnly})
+		}
+
+		for _, from := range svccfg.VolumesFrom {
+			service, mode, _ := strings.Cut(from, ":")
+			if _, ok := project.Services[service]; !ok {
+				return nil, fmt.Errorf("service %q: volumes_from must refer to another service, not %q", name, from)
+			}
+			if mode != "" {
+				mode = ":" + mode
+			}
+			container.VolumesFrom = append(container.VolumesFrom, containerName(service)+mode)
+		}
+
+		for service, dependency := range svccfg.DependsOn {
+			var condition types.ContainerCondition
+			switch dependency.Condition {
+			case "", compose.ServiceConditionStarted:
+				condition = types.ContainerStarted
+			case compose.ServiceConditionCompletedSuccessfully:
+				condition = types.ContainerSuccess
+			default:
+				return nil, fmt.Errorf("service %q: depends_on condition %q is not supported", name, dependency.Condition)
+			}
+			if container.DependsOn == nil {
+				container.DependsOn = make(map[string]types.ContainerCondition)
+			}
+			container.DependsOn[containerName(service)] = condition
+		}
+
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// mainService returns the only service that no other service depends on, directly or with volumes_from
+func mainService(services compose.Services) (string, error) {
+	if len(services) == 0 {
+		return "", errors.New("no services found")
+	}
+	dependedOn := make(map[string]bool)
+	for _, svccfg := range services {
+		for service := range svccfg.DependsOn {
+			dependedOn[service] = true
+		}
+		for _, from := range svccfg.VolumesFrom {
+			service, _, _ := strings.Cut(from, ":")
+			dependedOn[service] = true
+		}
+	}
+	var mains []string
+	for name := range services {
+		if !dependedOn[name] {
+			mains = append(mains, name)
+		}
+	}
+	slices.Sort(mains)
+	if len(mains) != 1 {
+		return "", fmt.Errorf("expected one main service that no other service depends on, found %q", mains)
+	}
+	return mains[0], nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	compose "github.com/compose-spec/compose-go/v2/types"
+	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs"
+	"github.com/defang-io/defang/src/pkg/docker"
+	"github.com/defang-io/defang/src/pkg/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestLoadCompose(t *testing.T) {
+	containers, err := LoadCompose("testdata/sidecar.yaml")
+	if err != nil {
+		t.Fatalf("LoadCompose() failed: %v", err)
+	}
+	if len(containers) != 3 {
+		t.Fatalf("expected 3 containers, got %d", len(containers))
+	}
+
+	main := containers[0]
+	if main.Name != ecs.ContainerName || main.Image != "alpine" || !*main.Essential {
+		t.Errorf("expected the essential main container first, got %+v", main)
+	}
+	if main.Env["MODE"] != "app" {
+		t.Errorf("expected MODE=app, got %v", main.Env)
+	}
+	if len(main.Ports) != 1 || main.Ports[0].Target != 8080 {
+		t.Errorf("expected port 8080, got %v", main.Ports)
+	}
+	if len(main.Volumes) != 1 || main.Volumes[0] != (types.TaskVolume{Source: "data", Target: "/data", ReadOnly: true}) {
+		t.Errorf("expected the read-only data volume, got %v", main.Volumes)
+	}
+	if main.DependsOn["init"] != types.ContainerSuccess || main.DependsOn["proxy"] != types.ContainerStarted {
+		t.Errorf("unexpected dependencies %v", main.DependsOn)
+	}
+
+	for _, helper := range containers[1:] {
+		if *helper.Essential {
+			t.Errorf("expected helper %q to be non-essential", helper.Name)
+		}
+	}
+	if proxy := containers[2]; proxy.Name != "proxy" || proxy.Cpus != 0.25 || proxy.Memory != 256*1024*1024 {
+		t.Errorf("expected the proxy with its reservations, got %+v", proxy)
+	}
+}
+
+func TestComposeContainersErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		services compose.Services
+	}{
+		{"no services", compose.Services{}},
+		{"two mains", compose.Services{"a": {Name: "a", Image: "alpine"}, "b": {Name: "b", Image: "alpine"}}},
+		{"cycle", compose.Services{
+			"a": {Name: "a", Image: "alpine", DependsOn: compose.DependsOnConfig{"b": {}}},
+			"b": {Name: "b", Image: "alpine", DependsOn: compose.DependsOnConfig{"a": {}}},
+		}},
+		{"build", compose.Services{"a": {Name: "a", Build: &compose.BuildConfig{Context: "."}}}},
+		{"bind mount", compose.Services{"a": {Name: "a", Image: "alpine", Volumes: []compose.ServiceVolumeConfig{{Type: compose.VolumeTypeBind, Source: "/tmp", Target: "/tmp"}}}}},
+		{"healthy", compose.Services{
+			"a": {Name: "a", Image: "alpine", DependsOn: compose.DependsOnConfig{"b": {Condition: compose.ServiceConditionHealthy}}},
+			"b": {Name: "b", Image: "alpine"},
+		}},
+		{"reserved name", compose.Services{
+			"a":    {Name: "a", Image: "alpine", DependsOn: compose.DependsOnConfig{"main": {}}},
+			"main": {Name: "main", Image: "alpine"},
+		}},
+		{"platforms", compose.Services{
+			"a": {Name: "a", Image: "alpine", Platform: "linux/amd64", VolumesFrom: []string{"b"}},
+			"b": {Name: "b", Image: "alpine", Platform: "linux/arm64"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := composeContainers(&compose.Project{Services: tt.services}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRunComposeDocker(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	d := docker.New()
+	if _, err := d.Ping(ctx); err != nil {
+		t.Skip("skipping test without a Docker daemon:", err)
+	}
+
+	containers, err := LoadCompose("testdata/sidecar.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetUp(ctx, containers); err != nil {
+		t.Fatal(err)
+	}
+
+	// The init container has exited by the time the main container starts; the main container reaches the proxy on localhost
+	id, err := d.Run(ctx, nil, "sh", "-c", "sleep 1; cat /data/ready && for i in 1 2 3 4 5 6 7 8 9 10; do wget -qO /dev/null http://localhost && exit 0; sleep 1; done; exit 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exit, waitErr := d.ContainerWait(ctx, *id, container.WaitConditionNextExit)
+	if _, err := d.GetInfo(ctx, id); err != nil {
+		t.Errorf("GetInfo() failed: %v", err)
+	}
+	if err := d.Tail(ctx, id); err != nil { // until the main container exits; then the rest of the task is stopped
+		t.Fatal(err)
+	}
+	select {
+	case resp := <-exit:
+		if resp.StatusCode != 0 {
+			t.Errorf("expected the main container to succeed, got exit code %d", resp.StatusCode)
+		}
+	case err := <-waitErr:
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/defang-io/defang/src/pkg/types"
+)
+
+func Exec(ctx context.Context, region Region, id types.TaskID, args ...string) error {
+	driver, err := createDriver(region)
+	if err != nil {
+		return err
+	}
+	return driver.Exec(ctx, id, args...)
+}
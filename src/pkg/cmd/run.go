@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/defang-io/defang/src/pkg/clouds/aws/ecs/cfn"
+	"github.com/defang-io/defang/src/pkg/registry"
 	"github.com/defang-io/defang/src/pkg/types"
 )
 
@@ -21,6 +22,13 @@ type RunContainerArgs struct {
 	Platform string
 	VpcID    string
 	SubnetID string
+	Ports    []types.Port
+	Ingress  []string // CIDRs that can connect to the ports, or "myip"
+	Egress   []string // CIDRs the task can connect to; all if empty
+	Spot     bool     // run on Spot capacity, with on-demand as fallback
+	Gpus     uint32   // number of NVIDIA GPUs
+
+	ComposeFile string // run the services of a Compose file as a single task, instead of the image
 }
 
 var cleanup = make(chan func())
@@ -47,18 +55,60 @@ func init() {
 }
 
 func Run(ctx context.Context, args RunContainerArgs) error {
-	driver, err := createDriver(args.Region, cfn.OptionVPCAndSubnetID(ctx, args.VpcID, args.SubnetID))
-	if err != nil { // VPC affects the cloudformation template
+	ingress, err := resolveCIDRs(ctx, args.Ingress)
+	if err != nil {
+		return err
+	}
+	egress, err := resolveCIDRs(ctx, args.Egress)
+	if err != nil {
 		return err
 	}
 
 	containers := []types.Container{
 		{
 			Image:    args.Image,
+			Gpus:     args.Gpus,
 			Memory:   args.Memory,
 			Platform: args.Platform,
+			Ports:    args.Ports,
 		},
 	}
+	if args.ComposeFile != "" {
+		if containers, err = LoadCompose(args.ComposeFile); err != nil {
+			return err
+		}
+		if args.Platform != "" {
+			for i := range containers {
+				containers[i].Platform = args.Platform
+			}
+		}
+	}
+
+	// Only the credentials for the registries of the images, because each one is stored in a secret
+	auths, err := registry.ParseAuthConfig(os.Getenv("DOCKER_AUTH_CONFIG"))
+	if err != nil {
+		return err
+	}
+	var registryCreds map[string]registry.Credentials
+	for _, container := range containers {
+		if host := registry.Host(container.Image); auths[host] != (registry.Credentials{}) {
+			if registryCreds == nil {
+				registryCreds = make(map[string]registry.Credentials)
+			}
+			registryCreds[host] = auths[host]
+		}
+	}
+
+	driver, err := createDriver(args.Region,
+		cfn.OptionVPCAndSubnetID(ctx, args.VpcID, args.SubnetID),
+		cfn.OptionSecurityGroup(ingress, egress),
+		cfn.OptionRegistryCredentials(registryCreds),
+		cfn.OptionSpot(args.Spot),
+	)
+	if err != nil { // VPC, security group, registry credentials, and capacity affect the cloudformation template
+		return err
+	}
+
 	if err := driver.SetUp(ctx, containers); err != nil {
 		return err
 	}
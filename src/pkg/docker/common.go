@@ -10,12 +10,16 @@ import (
 
 type ContainerID = types.TaskID
 
+// TaskLabel is the label of all the containers and volumes of a task, so they can be stopped and removed together
+const TaskLabel = "io.defang.crun.task"
+
+// PauseImage is the image of the container that keeps the network of a task with sidecars, like the pause container of a Kubernetes pod
+const PauseImage = "registry.k8s.io/pause:3.9"
+
 type Docker struct {
 	*client.Client
 
-	image    string
-	memory   uint64
-	platform string
+	containers []types.Container // the first one is the main container
 }
 
 func New() *Docker {
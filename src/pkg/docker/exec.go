@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"golang.org/x/term"
+)
+
+func (d Docker) Exec(ctx context.Context, id ContainerID, args ...string) error {
+	if len(args) == 0 {
+		args = []string{"sh"}
+	}
+
+	stdinFd := int(os.Stdin.Fd())
+	tty := term.IsTerminal(stdinFd) && term.IsTerminal(int(os.Stdout.Fd()))
+	var consoleSize *[2]uint
+	if tty {
+		if width, height, err := term.GetSize(stdinFd); err == nil {
+			consoleSize = &[2]uint{uint(height), uint(width)}
+		}
+	}
+
+	resp, err := d.ContainerExecCreate(ctx, *id, types.ExecConfig{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          tty,
+		ConsoleSize:  consoleSize,
+		Cmd:          args,
+	})
+	if err != nil {
+		return err
+	}
+
+	hijacked, err := d.ContainerExecAttach(ctx, resp.ID, types.ExecStartCheck{Tty: tty, ConsoleSize: consoleSize})
+	if err != nil {
+		return err
+	}
+	defer hijacked.Close()
+
+	if tty {
+		oldState, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(stdinFd, oldState)
+	}
+
+	go func() {
+		io.Copy(hijacked.Conn, os.Stdin)
+		hijacked.CloseWrite()
+	}()
+
+	if tty {
+		_, err = io.Copy(os.Stdout, hijacked.Reader) // no multiplexing with a TTY
+	} else {
+		_, err = stdcopy.StdCopy(os.Stdout, os.Stderr, hijacked.Reader)
+	}
+	if err != nil {
+		return err
+	}
+
+	inspect, err := d.ContainerExecInspect(ctx, resp.ID)
+	if err != nil {
+		return err
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("command exited with code %d", inspect.ExitCode)
+	}
+	return nil
+}
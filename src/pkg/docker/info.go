@@ -12,6 +12,12 @@ func (d Docker) GetInfo(ctx context.Context, id ContainerID) (*types.TaskInfo, e
 		return nil, err
 	}
 
+	if info.HostConfig.NetworkMode.IsContainer() {
+		// The ports are published by the pause container that owns the network of the task; see Run
+		owner := info.HostConfig.NetworkMode.ConnectedContainer()
+		return d.GetInfo(ctx, &owner)
+	}
+
 	// b, _ := json.MarshalIndent(info, "", "  ")
 	// println(string(b))
 
@@ -2,30 +2,277 @@ package docker
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/defang-io/defang/src/pkg"
+	pkgtypes "github.com/defang-io/defang/src/pkg/types"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
+// Run starts the containers of the task in the order of their dependencies; like awsvpc on ECS, they share one network,
+// so they can reach each other on localhost. It returns the ID of the main container.
 func (d Docker) Run(ctx context.Context, env map[string]string, cmd ...string) (ContainerID, error) {
-	resp, err := d.ContainerCreate(ctx, &container.Config{
-		Image: d.image,
-		Env:   mapToSlice(env),
-		Cmd:   cmd,
-	}, &container.HostConfig{
-		AutoRemove:      true, // --rm; FIXME: this causes "No such container" if the container exits early
-		PublishAllPorts: true, // -P
-		Resources: container.Resources{
-			Memory: int64(d.memory),
-		},
-	}, nil, parsePlatform(d.platform), "")
+	order, err := startOrder(d.containers)
 	if err != nil {
 		return nil, err
 	}
 
-	return &resp.ID, d.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{})
+	task := pkg.RandomID()
+	id, err := d.runTask(ctx, task, order, env, cmd)
+	if err != nil {
+		// Don't leave behind the containers that were already started; use a new context in case ctx was canceled
+		return nil, errors.Join(err, d.stopTask(context.Background(), task))
+	}
+	return id, nil
+}
+
+func (d Docker) runTask(ctx context.Context, task string, order []int, env map[string]string, cmd []string) (ContainerID, error) {
+	exposedPorts := make(nat.PortSet)
+	for _, c := range d.containers {
+		for _, port := range c.Ports {
+			exposedPorts[nat.Port(fmt.Sprintf("%d/%s", port.Target, portProtocol(port)))] = struct{}{}
+		}
+	}
+
+	var network string // the container that owns the network of the task, if the task has sidecars
+	if len(d.containers) > 1 {
+		// A sidecar can't own the network, because it might exit before the others start, like an init container
+		var err error
+		if network, err = d.startPause(ctx, task, exposedPorts); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make(map[string]string, len(d.containers)) // container name -> ID
+	exits := make(map[string]<-chan container.WaitResponse)
+	for _, i := range order {
+		c := d.containers[i]
+		for name, condition := range c.DependsOn {
+			if err := d.waitForCondition(ctx, ids[name], exits[name], condition); err != nil {
+				return nil, fmt.Errorf("container %q depends on %q: %w", c.Name, name, err)
+			}
+		}
+
+		config := &container.Config{
+			Image:      c.Image,
+			Env:        mapToSlice(c.Env),
+			Cmd:        c.Command,
+			Entrypoint: c.EntryPoint,
+			Labels:     map[string]string{TaskLabel: task},
+		}
+		if c.WorkDir != nil {
+			config.WorkingDir = *c.WorkDir
+		}
+		if i == 0 { // the main container
+			merged := maps.Clone(c.Env)
+			if merged == nil {
+				merged = make(map[string]string, len(env))
+			}
+			maps.Copy(merged, env)
+			config.Env = mapToSlice(merged)
+			if len(cmd) > 0 {
+				config.Cmd = cmd
+			}
+		}
+
+		var deviceRequests []container.DeviceRequest
+		if c.Gpus > 0 {
+			deviceRequests = []container.DeviceRequest{{ // --gpus N
+				Driver:       "nvidia",
+				Count:        int(c.Gpus),
+				Capabilities: [][]string{{"gpu"}},
+			}}
+		}
+		var mounts []mount.Mount
+		for _, v := range c.Volumes {
+			mounts = append(mounts, mount.Mount{
+				Type:          mount.TypeVolume,
+				Source:        task + "-" + v.Source, // task volumes are not shared with other tasks, like on ECS
+				Target:        v.Target,
+				ReadOnly:      v.ReadOnly,
+				VolumeOptions: &mount.VolumeOptions{Labels: map[string]string{TaskLabel: task}},
+			})
+		}
+		var volumesFrom []string
+		for _, v := range c.VolumesFrom {
+			name, mode, _ := strings.Cut(v, ":")
+			if mode != "" {
+				mode = ":" + mode
+			}
+			volumesFrom = append(volumesFrom, ids[name]+mode)
+		}
+		hostConfig := &container.HostConfig{
+			AutoRemove: true, // --rm; FIXME: this causes "No such container" if the container exits early
+			Mounts:     mounts,
+			Resources: container.Resources{
+				Memory:         int64(c.Memory),
+				NanoCPUs:       int64(c.Cpus * 1e9),
+				DeviceRequests: deviceRequests,
+			},
+			VolumesFrom: volumesFrom,
+		}
+		if network == "" {
+			config.ExposedPorts = exposedPorts
+			hostConfig.PublishAllPorts = true // -P
+		} else {
+			hostConfig.NetworkMode = container.NetworkMode("container:" + network)
+		}
+
+		resp, err := d.ContainerCreate(ctx, config, hostConfig, nil, parsePlatform(c.Platform), "")
+		if err != nil {
+			return nil, err
+		}
+		if isDependency(d.containers, c.Name, pkgtypes.ContainerComplete, pkgtypes.ContainerSuccess) {
+			// Wait before starting, because the container is removed when it exits
+			exits[c.Name], _ = d.ContainerWait(ctx, resp.ID, container.WaitConditionNextExit)
+		}
+		if err := d.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+			return nil, err
+		}
+		ids[c.Name] = resp.ID
+	}
+
+	id := ids[d.containers[0].Name]
+	return &id, nil
+}
+
+// startPause starts the container that owns the network of the task and publishes its ports; it's stopped with the task
+func (d Docker) startPause(ctx context.Context, task string, exposedPorts nat.PortSet) (string, error) {
+	config := &container.Config{
+		Image:        PauseImage,
+		ExposedPorts: exposedPorts,
+		Labels:       map[string]string{TaskLabel: task},
+	}
+	hostConfig := &container.HostConfig{
+		AutoRemove:      true, // --rm
+		PublishAllPorts: true, // -P
+	}
+	resp, err := d.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	if err != nil {
+		return "", err
+	}
+	if err := d.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// waitForCondition waits until the container that another container depends on has reached the condition
+func (d Docker) waitForCondition(ctx context.Context, id string, exit <-chan container.WaitResponse, condition pkgtypes.ContainerCondition) error {
+	switch condition {
+	case pkgtypes.ContainerStarted:
+		return nil // already started
+	case pkgtypes.ContainerComplete, pkgtypes.ContainerSuccess:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resp := <-exit:
+			if condition == pkgtypes.ContainerSuccess && resp.StatusCode != 0 {
+				return fmt.Errorf("exited with code %d", resp.StatusCode)
+			}
+			return nil
+		}
+	case pkgtypes.ContainerHealthy:
+		for {
+			info, err := d.ContainerInspect(ctx, id)
+			if err != nil {
+				return err
+			}
+			if info.State.Health == nil {
+				return fmt.Errorf("has no healthcheck")
+			}
+			switch info.State.Health.Status {
+			case types.Healthy:
+				return nil
+			case types.Unhealthy:
+				return fmt.Errorf("is unhealthy")
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported condition %q", condition)
+	}
+}
+
+// startOrder returns the indices of the containers, such that each one comes after the containers it depends on
+func startOrder(containers []pkgtypes.Container) ([]int, error) {
+	index := make(map[string]int, len(containers))
+	for i, c := range containers {
+		index[c.Name] = i
+	}
+	var order []int
+	state := make([]int, len(containers)) // 0: unvisited, 1: visiting, 2: done
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case 1:
+			return fmt.Errorf("container %q has a circular dependency", containers[i].Name)
+		case 2:
+			return nil
+		}
+		state[i] = 1
+		for _, name := range dependencies(containers[i]) {
+			j, ok := index[name]
+			if !ok {
+				return fmt.Errorf("container %q depends on unknown container %q", containers[i].Name, name)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = 2
+		order = append(order, i)
+		return nil
+	}
+	for i := range containers {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// dependencies returns the names of the containers that must be started before the given container, sorted
+func dependencies(c pkgtypes.Container) []string {
+	var names []string
+	for name := range c.DependsOn {
+		names = append(names, name)
+	}
+	for _, v := range c.VolumesFrom {
+		name, _, _ := strings.Cut(v, ":")
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func isDependency(containers []pkgtypes.Container, name string, conditions ...pkgtypes.ContainerCondition) bool {
+	for _, c := range containers {
+		if condition, ok := c.DependsOn[name]; ok && slices.Contains(conditions, condition) {
+			return true
+		}
+	}
+	return false
+}
+
+func portProtocol(port pkgtypes.Port) string {
+	if port.Protocol == "" {
+		return "tcp"
+	}
+	return port.Protocol
 }
 
 func mapToSlice(m map[string]string) []string {
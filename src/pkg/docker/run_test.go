@@ -2,6 +2,8 @@ package docker
 
 import (
 	"context"
+	"runtime"
+	"slices"
 	"testing"
 	"time"
 
@@ -16,7 +18,7 @@ func TestRun(t *testing.T) {
 
 	d := New()
 
-	err := d.SetUp(context.Background(), []types.Container{{Image: "alpine:latest", Platform: d.platform}})
+	err := d.SetUp(context.Background(), []types.Container{{Image: "alpine:latest", Platform: "linux/" + runtime.GOARCH}})
 
 	if err != nil {
 		t.Fatal(err)
@@ -88,3 +90,31 @@ func TestParsePlatform(t *testing.T) {
 		})
 	}
 }
+
+func TestStartOrder(t *testing.T) {
+	containers := []types.Container{
+		{Name: "main", DependsOn: map[string]types.ContainerCondition{"init": types.ContainerSuccess}, VolumesFrom: []string{"data:ro"}},
+		{Name: "init", VolumesFrom: []string{"data"}},
+		{Name: "data"},
+	}
+	order, err := startOrder(containers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2, 1, 0}; !slices.Equal(order, want) {
+		t.Errorf("expected order %v, got %v", want, order)
+	}
+
+	containers[2].DependsOn = map[string]types.ContainerCondition{"main": types.ContainerStarted}
+	if _, err := startOrder(containers); err == nil {
+		t.Error("expected an error for a circular dependency")
+	}
+	// SetUp checks the dependencies of the sidecars before pulling any image
+	if err := New().SetUp(context.Background(), containers); err == nil {
+		t.Error("expected SetUp to fail for a circular dependency")
+	}
+
+	if _, err := startOrder([]types.Container{{Name: "main", VolumesFrom: []string{"missing"}}}); err == nil {
+		t.Error("expected an error for an unknown container")
+	}
+}
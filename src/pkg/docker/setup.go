@@ -2,7 +2,6 @@ package docker
 
 import (
 	"context"
-	"errors"
 	"io"
 	"os"
 
@@ -12,19 +11,30 @@ import (
 )
 
 func (d *Docker) SetUp(ctx context.Context, containers []pkgtypes.Container) error {
-	if len(containers) != 1 {
-		return errors.New("only one container is supported with docker driver")
+	if _, err := startOrder(containers); err != nil {
+		return err
+	}
+	images := make([]pkgtypes.Container, 0, len(containers)+1)
+	images = append(images, containers...)
+	if len(containers) > 1 {
+		images = append(images, pkgtypes.Container{Image: PauseImage}) // owns the network of the task; see Run
+	}
+	for _, task := range images {
+		if err := d.pullImage(ctx, task.Image, task.Platform); err != nil {
+			return err
+		}
 	}
-	task := containers[0]
-	rc, err := d.ImagePull(ctx, task.Image, types.ImagePullOptions{Platform: task.Platform})
+	d.containers = containers
+	return nil
+}
+
+func (d *Docker) pullImage(ctx context.Context, image, platform string) error {
+	rc, err := d.ImagePull(ctx, image, types.ImagePullOptions{Platform: platform})
 	if err != nil {
 		return err
 	}
 	defer rc.Close()
 	_, err = io.Copy(contextAwareWriter{ctx, os.Stderr}, rc) // FIXME: this outputs JSON to stderr
-	d.image = task.Image
-	d.memory = task.Memory
-	d.platform = task.Platform
 	return err
 }
 
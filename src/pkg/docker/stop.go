@@ -2,10 +2,69 @@ package docker
 
 import (
 	"context"
+	"errors"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
 )
 
+// Stop stops the main container and the other containers of its task
 func (d Docker) Stop(ctx context.Context, id ContainerID) error {
-	return d.ContainerStop(ctx, *id, container.StopOptions{})
+	task, err := d.getTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := d.ContainerStop(ctx, *id, container.StopOptions{}); err != nil {
+		return err
+	}
+	return d.stopTask(ctx, task)
+}
+
+// getTask returns the label of the task of the container; empty if it was started by an older version
+func (d Docker) getTask(ctx context.Context, id ContainerID) (string, error) {
+	info, err := d.ContainerInspect(ctx, *id)
+	if err != nil {
+		return "", err
+	}
+	return info.Config.Labels[TaskLabel], nil
+}
+
+// stopTask stops the remaining containers of the task and removes its volumes, like ECS does when the main container exits
+func (d Docker) stopTask(ctx context.Context, task string) error {
+	if task == "" {
+		return nil
+	}
+	label := filters.NewArgs(filters.Arg("label", TaskLabel+"="+task))
+
+	containers, err := d.ContainerList(ctx, container.ListOptions{Filters: label})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, c := range containers {
+		removed, waitErr := d.ContainerWait(ctx, c.ID, container.WaitConditionRemoved) // the volumes are in use until then
+		if err := d.ContainerStop(ctx, c.ID, container.StopOptions{}); err != nil {
+			if !client.IsErrNotFound(err) { // it might have exited in the meantime
+				errs = append(errs, err)
+			}
+			continue
+		}
+		select {
+		case <-removed:
+		case <-waitErr:
+		}
+	}
+
+	volumes, err := d.VolumeList(ctx, volume.ListOptions{Filters: label})
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, v := range volumes.Volumes {
+		if err := d.VolumeRemove(ctx, v.Name, false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -2,13 +2,20 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/stdcopy"
 )
 
+// Tail follows the logs of the main container until it exits; then the rest of the task is stopped
 func (d Docker) Tail(ctx context.Context, id ContainerID) error {
+	task, err := d.getTask(ctx, id) // before the main container exits and is removed
+	if err != nil {
+		return err
+	}
+
 	rc, err := d.Client.ContainerLogs(ctx, *id, types.ContainerLogsOptions{
 		Follow:     true,
 		ShowStderr: true,
@@ -19,5 +26,8 @@ func (d Docker) Tail(ctx context.Context, id ContainerID) error {
 	}
 	defer rc.Close()
 	_, err = stdcopy.StdCopy(os.Stdout, os.Stderr, rc)
-	return err
+	if ctx.Err() != nil {
+		return err // still running; only stop the task when the main container exits
+	}
+	return errors.Join(err, d.stopTask(ctx, task))
 }
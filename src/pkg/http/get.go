@@ -2,7 +2,11 @@ package http
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strings"
 )
 
 func GetWithAuth(ctx context.Context, url, auth string) (*http.Response, error) {
@@ -13,3 +17,28 @@ func GetWithAuth(ctx context.Context, url, auth string) (*http.Response, error)
 	hreq.Header.Set("Authorization", auth)
 	return http.DefaultClient.Do(hreq)
 }
+
+// GetPublicIP returns the public IP address of the caller, as seen by AWS
+func GetPublicIP(ctx context.Context) (string, error) {
+	hreq, err := http.NewRequestWithContext(ctx, "GET", "https://checkip.amazonaws.com", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(hreq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get public IP: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	ip := strings.TrimSpace(string(body))
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("invalid public IP: %q", ip)
+	}
+	return ip, nil
+}
@@ -97,6 +97,10 @@ func (l *Local) Stop(ctx context.Context, taskID PID) error {
 	return syscall.Kill(-pid, syscall.SIGTERM) // negative pid kills the process group
 }
 
+func (l *Local) Exec(ctx context.Context, taskID PID, args ...string) error {
+	return errors.New("not implemented for local driver")
+}
+
 func (l *Local) GetInfo(ctx context.Context, taskID PID) (*types.TaskInfo, error) {
 	return nil, errors.New("not implemented for local driver")
 }
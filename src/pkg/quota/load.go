@@ -0,0 +1,102 @@
+package quota
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse reads quotas from YAML or JSON, like {cpus: 4, memory_mib: 8192}; fields that are not set are zero
+func Parse(data []byte) (Quotas, error) {
+	var q Quotas
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true) // catch typos
+	if err := decoder.Decode(&q); err != nil && err != io.EOF {
+		return q, err
+	}
+	var errs []error
+	for _, f := range q.fields() {
+		if f.value < 0 {
+			errs = append(errs, Violation{Field: f.name, Message: "must not be negative"})
+		}
+	}
+	return q, errors.Join(errs...)
+}
+
+// Override returns a copy of the quotas with the fields that are set in o
+func (q Quotas) Override(o Quotas) Quotas {
+	if o.Cpus != 0 {
+		q.Cpus = o.Cpus
+	}
+	if o.Gpus != 0 {
+		q.Gpus = o.Gpus
+	}
+	if o.MemoryMiB != 0 {
+		q.MemoryMiB = o.MemoryMiB
+	}
+	if o.Replicas != 0 {
+		q.Replicas = o.Replicas
+	}
+	if o.Services != 0 {
+		q.Services = o.Services
+	}
+	if o.ShmSizeMiB != 0 {
+		q.ShmSizeMiB = o.ShmSizeMiB
+	}
+	return q
+}
+
+// Cap returns a copy of the quotas where each field is at most the one in max, if it's set
+func (q Quotas) Cap(max Quotas) Quotas {
+	if max.Cpus != 0 && q.Cpus > max.Cpus {
+		q.Cpus = max.Cpus
+	}
+	if max.Gpus != 0 && q.Gpus > max.Gpus {
+		q.Gpus = max.Gpus
+	}
+	if max.MemoryMiB != 0 && q.MemoryMiB > max.MemoryMiB {
+		q.MemoryMiB = max.MemoryMiB
+	}
+	if max.Replicas != 0 && q.Replicas > max.Replicas {
+		q.Replicas = max.Replicas
+	}
+	if max.Services != 0 && q.Services > max.Services {
+		q.Services = max.Services
+	}
+	if max.ShmSizeMiB != 0 && q.ShmSizeMiB > max.ShmSizeMiB {
+		q.ShmSizeMiB = max.ShmSizeMiB
+	}
+	return q
+}
+
+// CheckLimits returns a violation for each field that exceeds the one in limits, if it's set, like the maximum size of a Fargate task
+func (q Quotas) CheckLimits(limits Quotas, source string) error {
+	var errs []error
+	limitFields := limits.fields()
+	for i, f := range q.fields() {
+		if limit := limitFields[i].value; limit != 0 && f.value > limit {
+			errs = append(errs, Violation{Field: f.name, Message: fmt.Sprintf("%v exceeds the %s limit of %v", f.value, source, limit)})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type field struct {
+	name  string
+	value float64
+}
+
+// fields returns the fields in a fixed order, by their name in the config
+func (q Quotas) fields() []field {
+	return []field{
+		{"cpus", float64(q.Cpus)},
+		{"gpus", float64(q.Gpus)},
+		{"memory_mib", float64(q.MemoryMiB)},
+		{"replicas", float64(q.Replicas)},
+		{"services", float64(q.Services)},
+		{"shm_size_mib", float64(q.ShmSizeMiB)},
+	}
+}
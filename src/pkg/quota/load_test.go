@@ -0,0 +1,57 @@
+package quota
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Quotas
+		wantErr string
+	}{
+		{"empty", "", Quotas{}, ""},
+		{"yaml", "cpus: 4\nmemory_mib: 8192\nreplicas: 2", Quotas{Cpus: 4, MemoryMiB: 8192, Replicas: 2}, ""},
+		{"json", `{"gpus": 1, "services": 10}`, Quotas{Gpus: 1, Services: 10}, ""},
+		{"typo", "cpu: 4", Quotas{}, "yaml: unmarshal errors:\n  line 1: field cpu not found in type quota.Quotas"},
+		{"negative", "cpus: -1\nservices: -2", Quotas{Cpus: -1, Services: -2}, "cpus: must not be negative\nservices: must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			} else if err == nil && tt.wantErr != "" {
+				t.Fatalf("Parse() error = nil, want %v", tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverrideAndCap(t *testing.T) {
+	defaults := Quotas{Cpus: 16, Gpus: 8, MemoryMiB: 65536, Replicas: 16, Services: 40, ShmSizeMiB: 30720}
+	project := Quotas{Cpus: 32, Replicas: 4}
+	policy := Quotas{Cpus: 8, Services: 10}
+
+	got := defaults.Override(project).Cap(policy)
+	want := Quotas{Cpus: 8, Gpus: 8, MemoryMiB: 65536, Replicas: 4, Services: 10, ShmSizeMiB: 30720}
+	if got != want {
+		t.Errorf("Override().Cap() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	limits := Quotas{Cpus: 16, MemoryMiB: 122880}
+	if err := (Quotas{Cpus: 16, MemoryMiB: 65536, Replicas: 100}).CheckLimits(limits, "Fargate"); err != nil {
+		t.Errorf("CheckLimits() = %v, want nil", err)
+	}
+	err := Quotas{Cpus: 32, MemoryMiB: 200000}.CheckLimits(limits, "Fargate")
+	want := "cpus: 32 exceeds the Fargate limit of 16\nmemory_mib: 200000 exceeds the Fargate limit of 122880"
+	if err == nil || err.Error() != want {
+		t.Errorf("CheckLimits() = %v, want %v", err, want)
+	}
+}
@@ -10,46 +10,66 @@ import (
 )
 
 type Quotas struct {
-	Cpus       float32
-	Gpus       uint32
-	MemoryMiB  float32
-	Replicas   uint32
-	Services   int
-	ShmSizeMiB float32
+	Cpus       float32 `yaml:"cpus,omitempty"`
+	Gpus       uint32  `yaml:"gpus,omitempty"`
+	MemoryMiB  float32 `yaml:"memory_mib,omitempty"`
+	Replicas   uint32  `yaml:"replicas,omitempty"`
+	Services   int     `yaml:"services,omitempty"`
+	ShmSizeMiB float32 `yaml:"shm_size_mib,omitempty"`
 }
 
+// Violation is a single problem with a field of a service, like a value that exceeds a quota
+type Violation struct {
+	Service string
+	Field   string // path of the field, like "deploy.resources.reservations.cpus"
+	Message string
+}
+
+func (v Violation) Error() string {
+	if v.Service == "" {
+		return v.Field + ": " + v.Message
+	}
+	return fmt.Sprintf("service %q: %s: %s", v.Service, v.Field, v.Message)
+}
+
+// Validate checks the service against the quotas and returns all violations at once, joined by newlines
 func (q Quotas) Validate(service *defangv1.Service) error {
+	var errs []error
+	violation := func(field, format string, args ...any) {
+		errs = append(errs, Violation{Service: service.Name, Field: field, Message: fmt.Sprintf(format, args...)}) // CodeInvalidArgument
+	}
+
 	if service.Name == "" {
-		return errors.New("service name is required") // CodeInvalidArgument
+		violation("name", "is required")
 	}
 
 	if service.Build != nil {
 		if service.Build.Context == "" {
-			return errors.New("build.context is required") // CodeInvalidArgument
+			violation("build.context", "is required")
 		}
 		if service.Build.ShmSize > q.ShmSizeMiB || service.Build.ShmSize < 0 {
-			return fmt.Errorf("build.shm_size exceeds quota (max %v MiB)", q.ShmSizeMiB) // CodeInvalidArgument
+			violation("build.shm_size", "exceeds quota (max %v MiB)", q.ShmSizeMiB)
 		}
 	} else {
 		if service.Image == "" {
-			return errors.New("missing image or build") // CodeInvalidArgument
+			violation("image", "missing image or build")
 		}
 	}
 
 	// hasHost := false
 	hasIngress := false
 	uniquePorts := make(map[uint32]bool)
-	for _, port := range service.Ports {
+	for i, port := range service.Ports {
 		if port.Target < 1 || port.Target > 32767 {
-			return fmt.Errorf("port %d is out of range", port.Target) // CodeInvalidArgument
+			violation(fmt.Sprintf("ports[%d].target", i), "port %d is out of range", port.Target)
 		}
 		if port.Mode == defangv1.Mode_INGRESS {
 			if port.Protocol == defangv1.Protocol_TCP || port.Protocol == defangv1.Protocol_UDP {
-				return fmt.Errorf("mode:INGRESS is not supported by protocol:%s", port.Protocol) // CodeInvalidArgument
+				violation(fmt.Sprintf("ports[%d].mode", i), "mode:INGRESS is not supported by protocol:%s", port.Protocol)
 			}
 		}
 		if uniquePorts[port.Target] {
-			return fmt.Errorf("duplicate port %d", port.Target) // CodeInvalidArgument
+			violation(fmt.Sprintf("ports[%d].target", i), "duplicate port %d", port.Target)
 		}
 		// hasHost = hasHost || port.Mode == v1.Mode_HOST
 		hasIngress = hasIngress || port.Mode == defangv1.Mode_INGRESS
@@ -58,67 +78,66 @@ func (q Quotas) Validate(service *defangv1.Service) error {
 	if service.Healthcheck != nil && len(service.Healthcheck.Test) > 0 {
 		// Technically this should test for <= but both interval and timeout have 30s as the default value in compose spec
 		if service.Healthcheck.Interval > 0 && service.Healthcheck.Interval < service.Healthcheck.Timeout {
-			return errors.New("invalid healthcheck: timeout must be less than the interval")
+			violation("healthcheck.timeout", "must be less than the interval")
 		}
 		switch service.Healthcheck.Test[0] {
 		case "CMD":
 			if hasIngress {
 				// For ingress ports, we derive the target group healthcheck path/port from the service healthcheck
 				if len(service.Healthcheck.Test) < 3 {
-					return errors.New("invalid CMD healthcheck: expected a command and URL")
-				}
-				if !strings.HasSuffix(service.Healthcheck.Test[1], "curl") && !strings.HasSuffix(service.Healthcheck.Test[1], "wget") {
-					return errors.New("invalid CMD healthcheck: expected curl or wget")
-				}
-				hasHttpUrl := false
-				for _, arg := range service.Healthcheck.Test[2:] {
-					if u, err := url.Parse(arg); err == nil && u.Scheme == "http" {
-						hasHttpUrl = true
-						break
+					violation("healthcheck.test", "invalid CMD healthcheck: expected a command and URL")
+				} else if !strings.HasSuffix(service.Healthcheck.Test[1], "curl") && !strings.HasSuffix(service.Healthcheck.Test[1], "wget") {
+					violation("healthcheck.test", "invalid CMD healthcheck: expected curl or wget")
+				} else {
+					hasHttpUrl := false
+					for _, arg := range service.Healthcheck.Test[2:] {
+						if u, err := url.Parse(arg); err == nil && u.Scheme == "http" {
+							hasHttpUrl = true
+							break
+						}
+					}
+					if !hasHttpUrl {
+						violation("healthcheck.test", "invalid CMD healthcheck; missing HTTP URL")
 					}
-				}
-				if !hasHttpUrl {
-					return errors.New("invalid CMD healthcheck; missing HTTP URL")
 				}
 			}
 		case "NONE":
 			if len(service.Healthcheck.Test) != 1 {
-				return errors.New("invalid NONE healthcheck; expected no arguments")
+				violation("healthcheck.test", "invalid NONE healthcheck; expected no arguments")
 			}
 			fallthrough // OK iff there are no ingress ports
 		case "CMD-SHELL": // OK iff there are no ingress ports; TODO: parse the command and check for curl/wget URL
 			if hasIngress {
-				return fmt.Errorf("ingress port requires a CMD healthcheck")
+				violation("healthcheck.test", "ingress port requires a CMD healthcheck")
 			}
 		default:
-			return fmt.Errorf("unsupported healthcheck: %v", service.Healthcheck.Test)
+			violation("healthcheck.test", "unsupported healthcheck: %v", service.Healthcheck.Test)
 		}
 	}
 
 	if service.Deploy != nil {
 		if service.Deploy.Replicas > q.Replicas {
-			return fmt.Errorf("replicas exceeds quota (max %d)", q.Replicas) // CodeInvalidArgument
+			violation("deploy.replicas", "exceeds quota (max %d)", q.Replicas)
 		}
 		if service.Deploy.Resources != nil && service.Deploy.Resources.Reservations != nil {
 			if service.Deploy.Resources.Reservations.Cpus > q.Cpus || service.Deploy.Resources.Reservations.Cpus < 0 {
-				return fmt.Errorf("cpus exceeds quota (max %v vCPU)", q.Cpus) // CodeInvalidArgument
+				violation("deploy.resources.reservations.cpus", "exceeds quota (max %v vCPU)", q.Cpus)
 			}
 			if service.Deploy.Resources.Reservations.Memory > q.MemoryMiB || service.Deploy.Resources.Reservations.Memory < 0 {
-				return fmt.Errorf("memory exceeds quota (max %v MiB)", q.MemoryMiB) // CodeInvalidArgument
+				violation("deploy.resources.reservations.memory", "exceeds quota (max %v MiB)", q.MemoryMiB)
 			}
-			for _, device := range service.Deploy.Resources.Reservations.Devices {
+			for i, device := range service.Deploy.Resources.Reservations.Devices {
+				field := fmt.Sprintf("deploy.resources.reservations.devices[%d]", i)
 				if len(device.Capabilities) != 1 || device.Capabilities[0] != "gpu" {
-					return errors.New("only GPU devices are supported") // CodeInvalidArgument
-				}
-				if device.Driver != "" && device.Driver != "nvidia" {
-					return errors.New("only nvidia GPU devices are supported") // CodeInvalidArgument
-				}
-				if q.Gpus == 0 || device.Count > q.Gpus {
-					return fmt.Errorf("gpu count exceeds quota (max %d)", q.Gpus) // CodeInvalidArgument
+					violation(field, "only GPU devices are supported")
+				} else if device.Driver != "" && device.Driver != "nvidia" {
+					violation(field, "only nvidia GPU devices are supported")
+				} else if q.Gpus == 0 || device.Count > q.Gpus {
+					violation(field, "GPU count exceeds quota (max %d)", q.Gpus)
 				}
 			}
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -15,42 +15,42 @@ func TestValidate(t *testing.T) {
 		{
 			name:    "empty service",
 			service: &defangv1.Service{},
-			wantErr: "service name is required",
+			wantErr: "name: is required\nimage: missing image or build",
 		},
 		{
 			name:    "no image, no build",
 			service: &defangv1.Service{Name: "test"},
-			wantErr: "missing image or build",
+			wantErr: `service "test": image: missing image or build`,
 		},
 		{
 			name:    "empty build",
 			service: &defangv1.Service{Name: "test", Build: &defangv1.Build{}},
-			wantErr: "build.context is required",
+			wantErr: `service "test": build.context: is required`,
 		},
 		{
 			name:    "shm size exceeds quota",
 			service: &defangv1.Service{Name: "test", Build: &defangv1.Build{Context: ".", ShmSize: 30721}},
-			wantErr: "build.shm_size exceeds quota (max 30720 MiB)",
+			wantErr: `service "test": build.shm_size: exceeds quota (max 30720 MiB)`,
 		},
 		{
 			name:    "port 0 out of range",
 			service: &defangv1.Service{Name: "test", Image: "asdf", Ports: []*defangv1.Port{{Target: 0}}},
-			wantErr: "port 0 is out of range",
+			wantErr: `service "test": ports[0].target: port 0 is out of range`,
 		},
 		{
 			name:    "port out of range",
 			service: &defangv1.Service{Name: "test", Image: "asdf", Ports: []*defangv1.Port{{Target: 33333}}},
-			wantErr: "port 33333 is out of range",
+			wantErr: `service "test": ports[0].target: port 33333 is out of range`,
 		},
 		{
 			name:    "ingress with UDP",
 			service: &defangv1.Service{Name: "test", Image: "asdf", Ports: []*defangv1.Port{{Target: 53, Mode: defangv1.Mode_INGRESS, Protocol: defangv1.Protocol_UDP}}},
-			wantErr: "mode:INGRESS is not supported by protocol:UDP",
+			wantErr: `service "test": ports[0].mode: mode:INGRESS is not supported by protocol:UDP`,
 		},
 		{
 			name:    "ingress with UDP",
 			service: &defangv1.Service{Name: "test", Image: "asdf", Ports: []*defangv1.Port{{Target: 80, Mode: defangv1.Mode_INGRESS, Protocol: defangv1.Protocol_TCP}}},
-			wantErr: "mode:INGRESS is not supported by protocol:TCP",
+			wantErr: `service "test": ports[0].mode: mode:INGRESS is not supported by protocol:TCP`,
 		},
 		{
 			name: "invalid healthcheck interval",
@@ -63,7 +63,7 @@ func TestValidate(t *testing.T) {
 					Timeout:  2,
 				},
 			},
-			wantErr: "invalid healthcheck: timeout must be less than the interval",
+			wantErr: `service "test": healthcheck.timeout: must be less than the interval`,
 		},
 		{
 			name: "invalid CMD healthcheck",
@@ -75,7 +75,7 @@ func TestValidate(t *testing.T) {
 					Test: []string{"CMD", "echo 1"},
 				},
 			},
-			wantErr: "invalid CMD healthcheck: expected a command and URL",
+			wantErr: `service "test": healthcheck.test: invalid CMD healthcheck: expected a command and URL`,
 		},
 		{
 			name: "CMD without curl or wget",
@@ -87,7 +87,7 @@ func TestValidate(t *testing.T) {
 					Test: []string{"CMD", "echo", "1"},
 				},
 			},
-			wantErr: "invalid CMD healthcheck: expected curl or wget",
+			wantErr: `service "test": healthcheck.test: invalid CMD healthcheck: expected curl or wget`,
 		},
 		{
 			name: "CMD without HTTP URL",
@@ -99,7 +99,7 @@ func TestValidate(t *testing.T) {
 					Test: []string{"CMD", "curl", "1"},
 				},
 			},
-			wantErr: "invalid CMD healthcheck; missing HTTP URL",
+			wantErr: `service "test": healthcheck.test: invalid CMD healthcheck; missing HTTP URL`,
 		},
 		{
 			name: "NONE with arguments",
@@ -110,7 +110,7 @@ func TestValidate(t *testing.T) {
 					Test: []string{"NONE", "echo", "1"},
 				},
 			},
-			wantErr: "invalid NONE healthcheck; expected no arguments",
+			wantErr: `service "test": healthcheck.test: invalid NONE healthcheck; expected no arguments`,
 		},
 		{
 			name: "CMD-SHELL with ingress",
@@ -122,7 +122,7 @@ func TestValidate(t *testing.T) {
 					Test: []string{"CMD-SHELL", "echo 1"},
 				},
 			},
-			wantErr: "ingress port requires a CMD healthcheck",
+			wantErr: `service "test": healthcheck.test: ingress port requires a CMD healthcheck`,
 		},
 		{
 			name: "NONE with ingress",
@@ -134,7 +134,7 @@ func TestValidate(t *testing.T) {
 					Test: []string{"NONE"},
 				},
 			},
-			wantErr: "ingress port requires a CMD healthcheck",
+			wantErr: `service "test": healthcheck.test: ingress port requires a CMD healthcheck`,
 		},
 		{
 			name: "unsupported healthcheck test",
@@ -145,7 +145,7 @@ func TestValidate(t *testing.T) {
 					Test: []string{"BLAH"},
 				},
 			},
-			wantErr: "unsupported healthcheck: [BLAH]",
+			wantErr: `service "test": healthcheck.test: unsupported healthcheck: [BLAH]`,
 		},
 		{
 			name: "too many replicas",
@@ -156,7 +156,7 @@ func TestValidate(t *testing.T) {
 					Replicas: 100,
 				},
 			},
-			wantErr: "replicas exceeds quota (max 16)",
+			wantErr: `service "test": deploy.replicas: exceeds quota (max 16)`,
 		},
 		{
 			name: "too many CPUs",
@@ -171,7 +171,7 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: "cpus exceeds quota (max 16 vCPU)",
+			wantErr: `service "test": deploy.resources.reservations.cpus: exceeds quota (max 16 vCPU)`,
 		},
 		{
 			name: "negative cpus",
@@ -186,7 +186,7 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: "cpus exceeds quota (max 16 vCPU)",
+			wantErr: `service "test": deploy.resources.reservations.cpus: exceeds quota (max 16 vCPU)`,
 		},
 		{
 			name: "too much memory",
@@ -201,7 +201,7 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: "memory exceeds quota (max 65536 MiB)",
+			wantErr: `service "test": deploy.resources.reservations.memory: exceeds quota (max 65536 MiB)`,
 		},
 		{
 			name: "negative memory",
@@ -216,7 +216,7 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: "memory exceeds quota (max 65536 MiB)",
+			wantErr: `service "test": deploy.resources.reservations.memory: exceeds quota (max 65536 MiB)`,
 		},
 		{
 			name: "only GPU",
@@ -233,7 +233,7 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: "only GPU devices are supported",
+			wantErr: `service "test": deploy.resources.reservations.devices[0]: only GPU devices are supported`,
 		},
 		{
 			name: "only nvidia GPU",
@@ -250,7 +250,7 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: "only nvidia GPU devices are supported",
+			wantErr: `service "test": deploy.resources.reservations.devices[0]: only nvidia GPU devices are supported`,
 		},
 		{
 			name: "too many GPUs",
@@ -267,7 +267,28 @@ func TestValidate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: "gpu count exceeds quota (max 8)",
+			wantErr: `service "test": deploy.resources.reservations.devices[0]: GPU count exceeds quota (max 8)`,
+		},
+		{
+			name: "multiple violations",
+			service: &defangv1.Service{
+				Name:  "test",
+				Ports: []*defangv1.Port{{Target: 80}, {Target: 80}},
+				Deploy: &defangv1.Deploy{
+					Replicas: 100,
+					Resources: &defangv1.Resources{
+						Reservations: &defangv1.Resource{
+							Cpus:   100,
+							Memory: 200000,
+						},
+					},
+				},
+			},
+			wantErr: `service "test": image: missing image or build
+service "test": ports[1].target: duplicate port 80
+service "test": deploy.replicas: exceeds quota (max 16)
+service "test": deploy.resources.reservations.cpus: exceeds quota (max 16 vCPU)
+service "test": deploy.resources.reservations.memory: exceeds quota (max 65536 MiB)`,
 		},
 		{
 			name: "valid service",
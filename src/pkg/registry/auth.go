@@ -0,0 +1,80 @@
+// Package registry handles credentials for private container registries.
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const DockerHub = "docker.io"
+
+// Credentials are the username and password (or token) for a registry; the JSON matches what ECS expects in the secret for repositoryCredentials.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type authConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth,omitempty"` // base64 of username:password
+		Username string `json:"username,omitempty"`
+		Password string `json:"password,omitempty"`
+	} `json:"auths"`
+}
+
+// ParseAuthConfig parses the JSON of DOCKER_AUTH_CONFIG or ~/.docker/config.json and returns the credentials by registry host
+func ParseAuthConfig(config string) (map[string]Credentials, error) {
+	if strings.TrimSpace(config) == "" {
+		return nil, nil
+	}
+	var ac authConfig
+	if err := json.Unmarshal([]byte(config), &ac); err != nil {
+		return nil, fmt.Errorf("invalid auth config: %w", err)
+	}
+
+	creds := make(map[string]Credentials, len(ac.Auths))
+	for server, auth := range ac.Auths {
+		cred := Credentials{Username: auth.Username, Password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth for registry %q: %w", server, err)
+			}
+			var ok bool
+			if cred.Username, cred.Password, ok = strings.Cut(string(decoded), ":"); !ok {
+				return nil, fmt.Errorf("invalid auth for registry %q: expected username:password", server)
+			}
+		}
+		if cred.Username == "" || cred.Password == "" {
+			continue // no credentials, like with a credential helper
+		}
+		creds[normalizeHost(server)] = cred
+	}
+	return creds, nil
+}
+
+// Host returns the registry host of the image, like "ghcr.io" or "docker.io" for Docker Hub
+func Host(image string) string {
+	host, _, ok := strings.Cut(image, "/")
+	if !ok || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return DockerHub
+	}
+	return normalizeHost(host)
+}
+
+// normalizeHost returns the host of a registry server address, like "https://index.docker.io/v1/"
+func normalizeHost(server string) string {
+	host := server
+	if _, after, ok := strings.Cut(host, "://"); ok {
+		host = after
+	}
+	host, _, _ = strings.Cut(host, "/")
+	host = strings.ToLower(host)
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return DockerHub
+	}
+	return host
+}
@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAuthConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    map[string]Credentials
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"auth", `{"auths":{"ghcr.io":{"auth":"dXNlcjpwYXNz"}}}`, map[string]Credentials{"ghcr.io": {"user", "pass"}}, false},
+		{"username and password", `{"auths":{"https://index.docker.io/v1/":{"username":"user","password":"pass"}}}`, map[string]Credentials{"docker.io": {"user", "pass"}}, false},
+		{"credential helper", `{"auths":{"quay.io":{}},"credsStore":"desktop"}`, map[string]Credentials{}, false},
+		{"password with colon", `{"auths":{"harbor.example.com:8443":{"auth":"cm9ib3Q6YTpi"}}}`, map[string]Credentials{"harbor.example.com:8443": {"robot", "a:b"}}, false},
+		{"invalid json", `{"auths":`, nil, true},
+		{"invalid auth", `{"auths":{"ghcr.io":{"auth":"dXNlcg=="}}}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAuthConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAuthConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAuthConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHost(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"alpine", "docker.io"},
+		{"library/alpine:3", "docker.io"},
+		{"docker.io/library/alpine", "docker.io"},
+		{"index.docker.io/library/alpine", "docker.io"},
+		{"ghcr.io/defang-io/cd:latest", "ghcr.io"},
+		{"harbor.example.com:8443/team/app", "harbor.example.com:8443"},
+		{"localhost/app", "localhost"},
+		{"public.ecr.aws/docker/library/alpine", "public.ecr.aws"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := Host(tt.image); got != tt.want {
+				t.Errorf("Host(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
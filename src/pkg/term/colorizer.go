@@ -43,6 +43,11 @@ func ForceColor(color bool) {
 	}
 }
 
+// GetSize returns the width and height of the terminal.
+func GetSize() (width, height int, err error) {
+	return term.GetSize(int(os.Stdout.Fd()))
+}
+
 func output(w *termenv.Output, c Color, msg string) (int, error) {
 	if len(msg) == 0 {
 		return 0, nil
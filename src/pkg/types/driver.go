@@ -18,19 +18,28 @@ const (
 	ContainerHealthy                     = "HEALTHY"
 )
 
+// Container is one of the containers of a task; the first one is the main container, which is the one that Run() overrides
 type Container struct {
 	Image       string
 	Name        string
 	Cpus        float32
+	Gpus        uint32 // number of NVIDIA GPUs
 	Memory      uint64
 	Platform    string
 	Essential   *bool
 	Volumes     []TaskVolume
 	VolumesFrom []string // container (default rw), container:rw, or container:ro
 	EntryPoint  []string
-	Command     []string // overridden by Run()
+	Command     []string          // overridden by Run()
+	Env         map[string]string // merged with the env of Run() for the main container
 	WorkDir     *string
 	DependsOn   map[string]ContainerCondition // container name -> condition
+	Ports       []Port                        // ports that accept ingress traffic
+}
+
+type Port struct {
+	Target   uint16
+	Protocol string // "tcp" (default) or "udp"
 }
 
 type TaskVolume struct {
@@ -46,7 +55,7 @@ type Driver interface {
 	Tail(ctx context.Context, taskID TaskID) error
 	// Query(ctx context.Context, taskID TaskID, since time.Time) error
 	Stop(ctx context.Context, taskID TaskID) error
-	// Exec(ctx context.Context, taskID TaskID, args ...string) error
+	Exec(ctx context.Context, taskID TaskID, args ...string) error
 	GetInfo(ctx context.Context, taskID TaskID) (*TaskInfo, error)
 	PutSecret(ctx context.Context, name, value string) error
 	// DeleteSecrets(ctx context.Context, names ...string) error
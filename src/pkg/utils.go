@@ -37,6 +37,14 @@ func Getenv(key, fallback string) string {
 	return fallback
 }
 
+// GetenvInt returns the integer value of an environment variable; defaults to the fallback if unset or invalid.
+func GetenvInt(key string, fallback int) int {
+	if val, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return val
+	}
+	return fallback
+}
+
 // GetenvBool returns the boolean value of an environment variable; defaults to false.
 func GetenvBool(key string) bool {
 	val, _ := strconv.ParseBool(os.Getenv(key))
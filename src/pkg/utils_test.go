@@ -21,6 +21,20 @@ func TestGetenvBool(t *testing.T) {
 	}
 }
 
+func TestGetenvInt(t *testing.T) {
+	if got := GetenvInt("BAR", 7); got != 7 {
+		t.Errorf("GetenvInt(BAR) = %d, want default 7", got)
+	}
+	os.Setenv("BAR", "30")
+	if got := GetenvInt("BAR", 7); got != 30 {
+		t.Errorf("GetenvInt(BAR) = %d, want 30", got)
+	}
+	os.Setenv("BAR", "thirty")
+	if got := GetenvInt("BAR", 7); got != 7 {
+		t.Errorf("GetenvInt(BAR) = %d, want default 7", got)
+	}
+}
+
 func TestIsValidServiceName(t *testing.T) {
 	tests := []struct {
 		name string